@@ -0,0 +1,114 @@
+package readability
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/go-shiori/go-readability/internal/re2go"
+)
+
+// Classifier checks whether the class name or id of a node matches one of
+// the heuristics used by the parser, e.g. whether the node is unlikely to
+// be part of the article.
+type Classifier interface {
+	Match(classOrID string) bool
+}
+
+// ClassifierFunc is an adapter to allow the use of ordinary function as
+// Classifier.
+type ClassifierFunc func(classOrID string) bool
+
+// Match calls f(classOrID).
+func (f ClassifierFunc) Match(classOrID string) bool {
+	return f(classOrID)
+}
+
+// Classifiers are the class and id heuristics used by the parser. A nil
+// classifier falls back to its default from `DefaultClassifiers`.
+type Classifiers struct {
+	// UnlikelyCandidates matches nodes that are unlikely to be part of the
+	// article, e.g. comments, sidebars and footers.
+	UnlikelyCandidates Classifier
+	// MaybeCandidate matches nodes that might be part of the article even
+	// though they are matched by UnlikelyCandidates.
+	MaybeCandidate Classifier
+	// Positive matches nodes whose class weight is increased.
+	Positive Classifier
+	// Negative matches nodes whose class weight is decreased.
+	Negative Classifier
+	// Byline matches nodes that contain the byline of the article.
+	Byline Classifier
+}
+
+// DefaultClassifiers returns the classifiers that use the patterns from
+// Readability.js.
+func DefaultClassifiers() Classifiers {
+	return Classifiers{
+		UnlikelyCandidates: ClassifierFunc(re2go.IsUnlikelyCandidates),
+		MaybeCandidate:     ClassifierFunc(re2go.MaybeItsACandidate),
+		Positive:           ClassifierFunc(re2go.IsPositiveClass),
+		Negative:           ClassifierFunc(re2go.IsNegativeClass),
+		Byline:             ClassifierFunc(re2go.IsByline),
+	}
+}
+
+// ExtendClassifier returns a classifier that matches when either the base
+// classifier or one of the additional patterns matches. The patterns are
+// regular expressions that are matched case-insensitively, so they can be
+// used to add CMS-specific classes or classes in other languages. If base
+// is nil, only the patterns are used.
+func ExtendClassifier(base Classifier, patterns ...string) (Classifier, error) {
+	rxPatterns := make([]*regexp.Regexp, len(patterns))
+	for i, pattern := range patterns {
+		rx, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid classifier pattern %q: %w", pattern, err)
+		}
+		rxPatterns[i] = rx
+	}
+
+	return ClassifierFunc(func(classOrID string) bool {
+		if base != nil && base.Match(classOrID) {
+			return true
+		}
+
+		for _, rx := range rxPatterns {
+			if rx.MatchString(classOrID) {
+				return true
+			}
+		}
+
+		return false
+	}), nil
+}
+
+// classifierOr returns the classifier, or the fallback if it's nil.
+func classifierOr(classifier Classifier, fallback func(string) bool) Classifier {
+	if classifier == nil {
+		return ClassifierFunc(fallback)
+	}
+	return classifier
+}
+
+// isUnlikelyCandidate checks whether the class and id of a node mark it
+// as unlikely to be part of the article.
+func (ps *Parser) isUnlikelyCandidate(matchString string) bool {
+	unlikely := classifierOr(ps.Classifiers.UnlikelyCandidates, re2go.IsUnlikelyCandidates)
+	maybe := classifierOr(ps.Classifiers.MaybeCandidate, re2go.MaybeItsACandidate)
+	return unlikely.Match(matchString) && !maybe.Match(matchString)
+}
+
+// isPositiveClass checks whether the class or id has positive weight.
+func (ps *Parser) isPositiveClass(classOrID string) bool {
+	return classifierOr(ps.Classifiers.Positive, re2go.IsPositiveClass).Match(classOrID)
+}
+
+// isNegativeClass checks whether the class or id has negative weight.
+func (ps *Parser) isNegativeClass(classOrID string) bool {
+	return classifierOr(ps.Classifiers.Negative, re2go.IsNegativeClass).Match(classOrID)
+}
+
+// isBylineClass checks whether the class and id mark a byline.
+func (ps *Parser) isBylineClass(matchString string) bool {
+	return classifierOr(ps.Classifiers.Byline, re2go.IsByline).Match(matchString)
+}
@@ -0,0 +1,72 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_ExtendClassifier(t *testing.T) {
+	classifier, err := ExtendClassifier(DefaultClassifiers().Negative, `werbung`, `^anzeige-`)
+	if err != nil {
+		t.Fatalf("failed to extend classifier: %v", err)
+	}
+
+	scenarios := map[string]bool{
+		"sidebar":         true,
+		"Werbung-Banner":  true,
+		"anzeige-top":     true,
+		"top-anzeige-box": false,
+		"article-body":    false,
+	}
+
+	for classOrID, expected := range scenarios {
+		if result := classifier.Match(classOrID); result != expected {
+			t.Errorf("\n"+
+				"class : %s\n"+
+				"want  : %v\n"+
+				"got   : %v", classOrID, expected, result)
+		}
+	}
+
+	if _, err := ExtendClassifier(nil, `(unclosed`); err == nil {
+		t.Errorf("invalid pattern, want error got nil")
+	}
+}
+
+func Test_Classifiers(t *testing.T) {
+	content := loremParagraph(20)
+	source := `<html><body><article>` + content +
+		`<div class="werbeblock"><p>` + strings.Repeat("Jetzt kaufen, nur heute! ", 10) + `</p></div>` +
+		content + `</article></body></html>`
+
+	// By default, the German ad block is not recognized
+	parser := NewParser()
+	article, err := parser.Parse(strings.NewReader(source), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if !strings.Contains(article.TextContent, "Jetzt kaufen") {
+		t.Fatalf("ad block is removed by default classifiers")
+	}
+
+	parser.Classifiers.UnlikelyCandidates, err = ExtendClassifier(parser.Classifiers.UnlikelyCandidates, `werbe`)
+	if err != nil {
+		t.Fatalf("failed to extend classifier: %v", err)
+	}
+
+	article, err = parser.Parse(strings.NewReader(source), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if strings.Contains(article.TextContent, "Jetzt kaufen") {
+		t.Errorf("ad block is not removed by extended classifier")
+	}
+
+	// Parser without classifiers falls back to the default ones
+	var emptyParser Parser
+	if !emptyParser.isUnlikelyCandidate("sidebar") || emptyParser.isUnlikelyCandidate("werbeblock") {
+		t.Errorf("parser without classifiers doesn't use the default ones")
+	}
+}
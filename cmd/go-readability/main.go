@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -14,6 +15,7 @@ import (
 
 	readability "github.com/go-shiori/go-readability"
 	"github.com/spf13/cobra"
+	"golang.org/x/net/html"
 )
 
 const index = `<!DOCTYPE HTML>
@@ -28,13 +30,24 @@ const index = `<!DOCTYPE HTML>
    <legend>Get readability content</legend>
    <p><label for="url">URL </label><input type="url" name="url" style="width:90%"></p>
    <p><input type="checkbox" name="text" value="true">text only</p>
+   <p><label for="wrap">wrap text at </label><input type="number" name="wrap" min="0" value="0"> characters</p>
+   <p><label for="format">format </label><select name="format">
+    <option value="html">HTML</option>
+    <option value="text">text</option>
+    <option value="markdown">Markdown</option>
+   </select></p>
    <p><input type="checkbox" name="metadata" value="true">only get the page's metadata</p>
+   <p><input type="checkbox" name="comments" value="true">include the comments in metadata</p>
+   <p><input type="checkbox" name="explain" value="true">explain the extraction using annotated HTML</p>
   </fieldset>
   <p><input type="submit"></p>
  </form>
  </body>
 </html>`
 
+// siteRules is the site-specific extraction rules loaded from --rules.
+var siteRules []readability.SiteRule
+
 func main() {
 	rootCmd := &cobra.Command{
 		Use:   "go-readability [flags] [source]",
@@ -46,7 +59,12 @@ func main() {
 
 	rootCmd.Flags().StringP("http", "l", "", "start the http server at the specified address")
 	rootCmd.Flags().BoolP("metadata", "m", false, "only print the page's metadata")
+	rootCmd.Flags().BoolP("comments", "c", false, "extract the page's comments into the metadata")
 	rootCmd.Flags().BoolP("text", "t", false, "only print the page's text")
+	rootCmd.Flags().StringP("format", "f", "html", "output format of the content: html, text or markdown")
+	rootCmd.Flags().IntP("wrap", "w", 0, "wrap the text output at the specified width, 0 to disable")
+	rootCmd.Flags().BoolP("explain", "e", false, "print the original page annotated with the parser's decisions")
+	rootCmd.Flags().StringP("rules", "r", "", "YAML or JSON file of site-specific extraction rules")
 
 	err := rootCmd.Execute()
 	if err != nil {
@@ -55,6 +73,15 @@ func main() {
 }
 
 func rootCmdHandler(cmd *cobra.Command, args []string) {
+	// Load site rules
+	if rulesPath, _ := cmd.Flags().GetString("rules"); rulesPath != "" {
+		var err error
+		siteRules, err = readability.LoadSiteRules(rulesPath)
+		if err != nil {
+			log.Fatalln(err)
+		}
+	}
+
 	// Start HTTP server
 	httpListen, _ := cmd.Flags().GetString("http")
 	if httpListen != "" {
@@ -65,9 +92,23 @@ func rootCmdHandler(cmd *cobra.Command, args []string) {
 
 	// Get cmd parameter
 	metadataOnly, _ := cmd.Flags().GetBool("metadata")
+	withComments, _ := cmd.Flags().GetBool("comments")
 	textOnly, _ := cmd.Flags().GetBool("text")
+	format, _ := cmd.Flags().GetString("format")
+	wrapWidth, _ := cmd.Flags().GetInt("wrap")
+	explain, _ := cmd.Flags().GetBool("explain")
+	if textOnly {
+		format = "text"
+	}
+
 	if len(args) > 0 {
-		content, err := getContent(args[0], metadataOnly, textOnly)
+		var content string
+		var err error
+		if explain {
+			content, err = getExplanation(context.Background(), args[0])
+		} else {
+			content, err = getContent(context.Background(), args[0], metadataOnly, withComments, format, wrapWidth)
+		}
 		if err != nil {
 			log.Fatalln(err)
 		}
@@ -80,7 +121,15 @@ func rootCmdHandler(cmd *cobra.Command, args []string) {
 
 func httpHandler(w http.ResponseWriter, r *http.Request) {
 	metadataOnly, _ := strconv.ParseBool(r.URL.Query().Get("metadata"))
+	withComments, _ := strconv.ParseBool(r.URL.Query().Get("comments"))
 	textOnly, _ := strconv.ParseBool(r.URL.Query().Get("text"))
+	format := r.URL.Query().Get("format")
+	wrapWidth, _ := strconv.Atoi(r.URL.Query().Get("wrap"))
+	explain, _ := strconv.ParseBool(r.URL.Query().Get("explain"))
+	if textOnly {
+		format = "text"
+	}
+
 	url := r.URL.Query().Get("url")
 	if url == "" {
 		if _, err := w.Write([]byte(index)); err != nil {
@@ -90,16 +139,26 @@ func httpHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	} else {
 		log.Println("process URL", url)
-		content, err := getContent(url, metadataOnly, textOnly)
+		var content string
+		var err error
+		if explain {
+			content, err = getExplanation(r.Context(), url)
+		} else {
+			content, err = getContent(r.Context(), url, metadataOnly, withComments, format, wrapWidth)
+		}
 		if err != nil {
 			log.Println(err)
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		if metadataOnly {
+		if explain {
+			w.Header().Set("Content-Type", "text/html")
+		} else if metadataOnly {
 			w.Header().Set("Content-Type", "application/json")
-		} else if textOnly {
+		} else if format == "text" {
 			w.Header().Set("Content-Type", "text/plain")
+		} else if format == "markdown" {
+			w.Header().Set("Content-Type", "text/markdown")
 		}
 		if _, err := w.Write([]byte(content)); err != nil {
 			log.Println(err)
@@ -109,22 +168,27 @@ func httpHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func getContent(srcPath string, metadataOnly, textOnly bool) (string, error) {
-	// Open or fetch web page that will be parsed
-	var (
-		pageURL   *nurl.URL
-		srcReader io.Reader
-	)
+func getContent(ctx context.Context, srcPath string, metadataOnly, withComments bool, format string, wrapWidth int) (string, error) {
+	// Make sure the output format is known
+	switch format {
+	case "", "html", "text", "markdown":
+	default:
+		return "", fmt.Errorf("unknown output format: %s", format)
+	}
+
+	parser := readability.NewParser()
+	parser.SiteRules = siteRules
+	parser.ExtractComments = withComments
 
+	// Fetch or open web page that will be parsed
+	var article readability.Article
 	if _, isURL := validateURL(srcPath); isURL {
-		resp, err := http.Get(srcPath)
+		var err error
+		article, err = readability.FromURLWithOptions(ctx, srcPath,
+			readability.WithParser(&parser), readability.WithReaderableCheck())
 		if err != nil {
-			return "", fmt.Errorf("failed to fetch web page: %v", err)
+			return "", fmt.Errorf("failed to parse page: %v", err)
 		}
-		defer resp.Body.Close()
-
-		pageURL = resp.Request.URL
-		srcReader = resp.Body
 	} else {
 		srcFile, err := os.Open(srcPath)
 		if err != nil {
@@ -132,33 +196,48 @@ func getContent(srcPath string, metadataOnly, textOnly bool) (string, error) {
 		}
 		defer srcFile.Close()
 
-		pageURL, _ = nurl.ParseRequestURI("http://fakehost.com")
-		srcReader = srcFile
-	}
-
-	// Use tee so the reader can be used twice
-	buf := bytes.NewBuffer(nil)
-	tee := io.TeeReader(srcReader, buf)
+		// Use tee so the reader can be used twice
+		buf := bytes.NewBuffer(nil)
+		tee := io.TeeReader(srcFile, buf)
 
-	// Make sure the page is readable
-	if !readability.Check(tee) {
-		return "", fmt.Errorf("failed to parse page: the page is not readable")
-	}
+		// Make sure the page is readable
+		if !readability.Check(tee) {
+			return "", fmt.Errorf("failed to parse page: the page is not readable")
+		}
 
-	// Get readable content from the reader
-	article, err := readability.FromReader(buf, pageURL)
-	if err != nil {
-		return "", fmt.Errorf("failed to parse page: %v", err)
+		// Get readable content from the reader
+		pageURL, _ := nurl.ParseRequestURI("http://fakehost.com")
+		article, err = parser.Parse(buf, pageURL)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse page: %v", err)
+		}
 	}
 
 	// Return the article (or its metadata)
 	if metadataOnly {
 		metadata := map[string]interface{}{
-			"title":   article.Title,
-			"byline":  article.Byline,
-			"excerpt": article.Excerpt,
-			"image":   article.Image,
-			"favicon": article.Favicon,
+			"url":            article.URL,
+			"title":          article.Title,
+			"byline":         article.Byline,
+			"excerpt":        article.Excerpt,
+			"image":          article.Image,
+			"favicon":        article.Favicon,
+			"canonicalURL":   article.CanonicalURL,
+			"dir":            article.Dir,
+			"language":       article.LanguageTag,
+			"languageSource": article.LanguageSource,
+			"keywords":       article.Keywords,
+			"section":        article.Section,
+			"encoding":       article.Encoding,
+			"found":          article.Found,
+			"confidence":     article.Confidence,
+			"extraction":     article.Extraction,
+			"wordCount":      article.WordCount,
+			"readingTime":    article.ReadingTime.String(),
+		}
+
+		if withComments {
+			metadata["comments"] = article.Comments
 		}
 
 		prettyJSON, err := json.MarshalIndent(&metadata, "", "    ")
@@ -169,11 +248,48 @@ func getContent(srcPath string, metadataOnly, textOnly bool) (string, error) {
 		return string(prettyJSON), nil
 	}
 
-	if textOnly {
-		return article.TextContent, nil
+	switch format {
+	case "text":
+		return article.Text(wrapWidth), nil
+	case "markdown":
+		return article.Markdown(), nil
+	default:
+		return article.Content, nil
+	}
+}
+
+func getExplanation(ctx context.Context, srcPath string) (string, error) {
+	parser := readability.NewParser()
+	parser.SiteRules = siteRules
+
+	// Fetch or open web page that will be explained
+	var doc *html.Node
+	if _, isURL := validateURL(srcPath); isURL {
+		var err error
+		doc, err = readability.ExplainURL(ctx, srcPath, readability.WithParser(&parser))
+		if err != nil {
+			return "", fmt.Errorf("failed to explain page: %v", err)
+		}
+	} else {
+		srcFile, err := os.Open(srcPath)
+		if err != nil {
+			return "", fmt.Errorf("failed to open source file: %v", err)
+		}
+		defer srcFile.Close()
+
+		pageURL, _ := nurl.ParseRequestURI("http://fakehost.com")
+		doc, err = parser.Explain(srcFile, pageURL)
+		if err != nil {
+			return "", fmt.Errorf("failed to explain page: %v", err)
+		}
+	}
+
+	buf := bytes.NewBuffer(nil)
+	if err := html.Render(buf, doc); err != nil {
+		return "", fmt.Errorf("failed to render explanation: %v", err)
 	}
 
-	return article.Content, nil
+	return buf.String(), nil
 }
 
 func validateURL(path string) (*nurl.URL, bool) {
@@ -0,0 +1,65 @@
+package readability
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	// ErrInvalidURL is returned when the URL of the page can't be parsed.
+	ErrInvalidURL = errors.New("invalid URL")
+
+	// ErrNotHTML is returned when the fetched page is not a HTML document.
+	ErrNotHTML = errors.New("URL is not a HTML document")
+
+	// ErrBodyTooLarge is returned when the fetched page is larger than
+	// the limit that is set using `WithMaxBodySize`.
+	ErrBodyTooLarge = errors.New("the page is too large")
+
+	// ErrNotReadable is returned when the page is not readable according
+	// to `Parser.CheckDocument`, e.g. when `WithReaderableCheck` is used.
+	ErrNotReadable = errors.New("the page is not readable")
+
+	// ErrTooManyElements is returned when the document has more elements
+	// than `Parser.MaxElemsToParse`. The returned error is a
+	// `*TooManyElementsError` that keeps the number of elements.
+	ErrTooManyElements = errors.New("documents too large")
+
+	// ErrNoContent is returned when no article content can be found in
+	// the document and `Parser.RequireContent` is enabled.
+	ErrNoContent = errors.New("no article content found")
+
+	// ErrInvalidSiteRule is returned when a site rule can't be used, e.g.
+	// because its selector is invalid.
+	ErrInvalidSiteRule = errors.New("invalid site rule")
+)
+
+// TooManyElementsError is returned when the document has more elements
+// than `Parser.MaxElemsToParse`. It matches `ErrTooManyElements` when
+// checked using `errors.Is`.
+type TooManyElementsError struct {
+	Count int
+	Max   int
+}
+
+func (e *TooManyElementsError) Error() string {
+	return fmt.Sprintf("documents too large: %d elements", e.Count)
+}
+
+// Is makes the error matches `ErrTooManyElements`.
+func (e *TooManyElementsError) Is(target error) bool {
+	return target == ErrTooManyElements
+}
+
+// HTTPStatusError is returned when the page is fetched with unexpected
+// HTTP status code.
+type HTTPStatusError struct {
+	StatusCode int
+	URL        string
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("unexpected status code %d (%s) for %s",
+		e.StatusCode, http.StatusText(e.StatusCode), e.URL)
+}
@@ -0,0 +1,122 @@
+package readability
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	nurl "net/url"
+)
+
+// Fetcher fetches the web page in the specified URL. The caller is
+// responsible to close the body of the returned response.
+type Fetcher interface {
+	Fetch(ctx context.Context, pageURL *nurl.URL) (*http.Response, error)
+}
+
+// FetcherFunc is an adapter to allow the use of ordinary functions as Fetcher.
+type FetcherFunc func(ctx context.Context, pageURL *nurl.URL) (*http.Response, error)
+
+// Fetch calls f(ctx, pageURL).
+func (f FetcherFunc) Fetch(ctx context.Context, pageURL *nurl.URL) (*http.Response, error) {
+	return f(ctx, pageURL)
+}
+
+// HTTPFetcher is Fetcher that downloads the web page using HTTP GET request.
+type HTTPFetcher struct {
+	// Client is the HTTP client used to send the request. If nil,
+	// `http.DefaultClient` will be used.
+	Client *http.Client
+	// RequestModifiers are applied to the request before it's sent,
+	// e.g. to set the user agent or cookies.
+	RequestModifiers []RequestWith
+}
+
+// Fetch sends GET request to pageURL and returns its response.
+func (f HTTPFetcher) Fetch(ctx context.Context, pageURL *nurl.URL) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, modifier := range f.RequestModifiers {
+		modifier(req)
+	}
+
+	client := f.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return client.Do(req)
+}
+
+// readResponse validates the response of the fetched page and reads its
+// body. The status code must be 2xx and the page must be a HTML or XHTML
+// document. If the Content-Type header is missing, the content type is
+// sniffed from the body. If maxBodySize is bigger than zero, the body
+// that is larger than it will be rejected.
+func readResponse(resp *http.Response, pageURL *nurl.URL, maxBodySize int64) ([]byte, error) {
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, &HTTPStatusError{StatusCode: resp.StatusCode, URL: pageURL.String()}
+	}
+
+	// Check the content type that is declared by the server
+	contentType := resp.Header.Get("Content-Type")
+	if contentType != "" && !isHTMLContentType(contentType) {
+		return nil, fmt.Errorf("%w: content type is %q", ErrNotHTML, contentType)
+	}
+
+	// Read the body while making sure it's not too large
+	if maxBodySize > 0 && resp.ContentLength > maxBodySize {
+		return nil, fmt.Errorf("%w: %d bytes", ErrBodyTooLarge, resp.ContentLength)
+	}
+
+	var body io.Reader = resp.Body
+	if maxBodySize > 0 {
+		body = io.LimitReader(resp.Body, maxBodySize+1)
+	}
+
+	content, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the page: %w", err)
+	}
+
+	if maxBodySize > 0 && int64(len(content)) > maxBodySize {
+		return nil, fmt.Errorf("%w: more than %d bytes", ErrBodyTooLarge, maxBodySize)
+	}
+
+	// If content type is missing, sniff it from the content
+	if contentType == "" && !isHTMLContent(content) {
+		return nil, fmt.Errorf("%w: sniffed content type is %q", ErrNotHTML, http.DetectContentType(content))
+	}
+
+	return content, nil
+}
+
+// isHTMLContentType checks if the Content-Type header is HTML or XHTML.
+func isHTMLContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return mediaType == "text/html" || mediaType == "application/xhtml+xml"
+}
+
+// isHTMLContent sniffs the content to check if it's a HTML document.
+// XHTML documents are sniffed as XML, so for them the root is checked.
+func isHTMLContent(content []byte) bool {
+	mediaType, _, _ := mime.ParseMediaType(http.DetectContentType(content))
+	switch mediaType {
+	case "text/html":
+		return true
+	case "text/xml":
+		head := content[:min(len(content), 1024)]
+		return bytes.Contains(bytes.ToLower(head), []byte("<html"))
+	default:
+		return false
+	}
+}
@@ -0,0 +1,65 @@
+package readability
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	nurl "net/url"
+	"strings"
+	"testing"
+)
+
+func Test_readResponse(t *testing.T) {
+	const page = "<!DOCTYPE html><html><body><p>Hello</p></body></html>"
+	const xhtml = `<?xml version="1.0" encoding="UTF-8"?><html xmlns="http://www.w3.org/1999/xhtml"><body></body></html>`
+
+	scenarios := []struct {
+		name        string
+		statusCode  int
+		contentType string
+		body        string
+		maxBodySize int64
+		wantErr     error
+	}{
+		{"html", http.StatusOK, "text/html; charset=utf-8", page, 0, nil},
+		{"xhtml", http.StatusOK, "application/xhtml+xml", xhtml, 0, nil},
+		{"sniffed html", http.StatusOK, "", page, 0, nil},
+		{"sniffed xhtml", http.StatusOK, "", xhtml, 0, nil},
+		{"sniffed text", http.StatusOK, "", "just a text", 0, ErrNotHTML},
+		{"json", http.StatusOK, "application/json", `{"a":1}`, 0, ErrNotHTML},
+		{"not found", http.StatusNotFound, "text/html", page, 0, &HTTPStatusError{}},
+		{"server error", http.StatusInternalServerError, "text/html", page, 0, &HTTPStatusError{}},
+		{"small enough", http.StatusOK, "text/html", page, int64(len(page)), nil},
+		{"too large", http.StatusOK, "text/html", page, 10, ErrBodyTooLarge},
+	}
+
+	pageURL, _ := nurl.Parse("http://fakehost/page")
+	for _, scenario := range scenarios {
+		resp := &http.Response{
+			StatusCode:    scenario.statusCode,
+			Header:        http.Header{},
+			Body:          io.NopCloser(strings.NewReader(scenario.body)),
+			ContentLength: -1,
+		}
+
+		if scenario.contentType != "" {
+			resp.Header.Set("Content-Type", scenario.contentType)
+		}
+
+		content, err := readResponse(resp, pageURL, scenario.maxBodySize)
+
+		var statusErr *HTTPStatusError
+		switch {
+		case scenario.wantErr == nil && err != nil:
+			t.Errorf("%s: want no error got %v", scenario.name, err)
+		case scenario.wantErr == nil && string(content) != scenario.body:
+			t.Errorf("%s: want content %q got %q", scenario.name, scenario.body, content)
+		case errors.As(scenario.wantErr, &statusErr):
+			if !errors.As(err, &statusErr) || statusErr.StatusCode != scenario.statusCode {
+				t.Errorf("%s: want HTTPStatusError %d got %v", scenario.name, scenario.statusCode, err)
+			}
+		case scenario.wantErr != nil && !errors.Is(err, scenario.wantErr):
+			t.Errorf("%s: want %v got %v", scenario.name, scenario.wantErr, err)
+		}
+	}
+}
@@ -0,0 +1,313 @@
+package readability
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/language"
+)
+
+// LanguageSource is where the article language is found.
+type LanguageSource string
+
+const (
+	// LanguageFromHTML is the lang attribute of the html element.
+	LanguageFromHTML LanguageSource = "html"
+	// LanguageFromHeader is the Content-Language header, passed through
+	// `Parser.LanguageHint`.
+	LanguageFromHeader LanguageSource = "header"
+	// LanguageFromMetaHTTPEquiv is the `<meta http-equiv="content-language">`.
+	LanguageFromMetaHTTPEquiv LanguageSource = "meta-http-equiv"
+	// LanguageFromOpenGraph is the `og:locale` meta tag.
+	LanguageFromOpenGraph LanguageSource = "og:locale"
+	// LanguageFromJSONLD is the `inLanguage` property in JSON-LD.
+	LanguageFromJSONLD LanguageSource = "json-ld"
+	// LanguageFromText means the language is detected from the article text.
+	LanguageFromText LanguageSource = "text"
+)
+
+// Limits of the text that is used to detect the language.
+const (
+	minDetectionLetters = 40
+	maxDetectionLetters = 5000
+	// minTrigramCoverage is the min percentage of the text trigrams
+	// that must exist in the profile of the detected language.
+	minTrigramCoverage = 15
+)
+
+// trigramProfiles are the most common trigrams of the languages written in
+// Latin script, sorted from the most common. Spaces mark the word boundaries.
+var trigramProfiles = map[string][]string{
+	"en": strings.Split(" th|the|he | an|nd |and|ed | of|of | to|ing|ng |to | in|in |er |is |ion| a |on |at |es |re |ent|tio| co|ly | be| wa|or |hat|tha|for| fo|as |his|was| it|ati|ter| ha|st |ve |it | re|are|ere|her| is|ts |al |thi|all| wh|ith|wit| wi|rs |ver|e t|d t|e a|s a|s t|n t| on|e o", "|"),
+	"fr": strings.Split(" de|es |de |le | le|ent| la|la |les|nt | et|et |re |ion|que| qu|ue | co|e d|tio| pa|s d|e l|men|des| un| d'| l'|une|ur | pr|ne |ait|est| es| po|par|ons|our|e s|s l|ant| à |ans|ell|lle| so|eme|ous| se|ai |ais|dan| da|e p|ce |te |ie |qui|sur| su|it |e c", "|"),
+	"de": strings.Split("en |er | de|der|ie |ch | di|die|ein|sch|ich|nd |und| un|den|cht|te |in |ine| ei|gen|ten|es |ung|ng |che|nde| da|das|ber| zu|ver| ve|ist| is|auf| au|mit| mi|ent|ere|eit|hen| ge|ges| si|sie|nen|ren|ter|lic|ach|nic| ni|rde|ese|sen|n d|r d|t d|e d", "|"),
+	"es": strings.Split(" de|de |os | la|la |el | el|es |ión| en|en | qu|que|ue |as |ent|aci|ció| co|do | lo|los|nte| se|con| pa|ado|par|ara|est| es|ra |por| po|del|las| un|una|er |o d|e l|a d|s d|e d|a l| ca|sta|res|ien|nto|ero|ido|ta |al | su| re|an | pr|mos|cia|te |ica", "|"),
+	"pt": strings.Split(" de|de |os |do | do|ção|ão | qu|que|ue | co|da | da|as | a |es |nte|ent| pa|com|ara|par|em | em|est| se|ões|dos| no|uma|um |não| nã|ado|men| pr|ra |o d|e d|a d|s d| e |o p|ar |ica|ida|con| ca|mai| ma|ais|ele|to |ia |ro |sta|ter|res| re|o e|a p", "|"),
+	"it": strings.Split(" di|di |la | la|che| ch|he |to | il|il |re |ell|del| de|lla|one| co|zio|ion|ne |ta | pe|per|er |ent|nte| in|ato|non| no| e |le |gli|no |are|ale|con|ti | un|na |ono|ere|i d|e d|o d|a d| so|al |ia |e l|o a| è |ed |ra |sta| st|ess|ant|men|una|lle", "|"),
+	"nl": strings.Split("en | de|de |het| he|et |van| va|an |een| ee|er |ij | en|in | in|ing|aar|oor|ijk|gen|den|nde| ge|ver| ve|te | zi|cht|sch| op|op |dat| da|ter|ook| oo|ie |zij|n d|n h|t h|e v|n v| wo|ord|wor| te|eer|nie| ni|aan| me|met|ere|ste|el |lij|ven|sta", "|"),
+	"id": strings.Split("an |ang| me|kan|ng | da|yan| ya| di|dan|ara|men|nya|ya |ata| pe|ber| be|ala|ah |eng|ter|per|ak |ini|in |lah| in|da |ran|ung|era| se|pen|n d|n p|n m|aka|asi|ega|i d|uk |unt| un|tuk|gan|a d|ena|dal|ngk|ari| ke|ada|ela|ama|erk|sa |ole|leh", "|"),
+	"sv": strings.Split(" de|en |er | oc|och|ch |för| fö|att| at|tt |ar |de |et |an | so|som|om |ing| in| en|den|är | är|ade| ha|and|nde|lig|ll | ti|til|ill|med| me|ter|ta |der|var| va|ra |ska| sk|na |het|ens|lla| av|av |ör |sta|n s|nin|gen|kan| ka|int", "|"),
+}
+
+// scriptWordProfiles are the common words of the languages that share the
+// same script, keyed by the ISO 15924 code of the script. The writing
+// system alone can't tell these languages apart, e.g. Persian and Urdu
+// are written in Arabic script as well.
+var scriptWordProfiles = map[string]map[string][]string{
+	"Arab": {
+		"ar": strings.Split("في|من|على|إلى|أن|التي|الذي|عن|مع|هذا|كان|هذه", "|"),
+		"fa": strings.Split("در|به|از|که|این|را|با|است|می|آن|برای|شد", "|"),
+		"ur": strings.Split("کے|میں|کی|ہے|اور|سے|کو|کا|یہ|ہیں|نے|پر", "|"),
+	},
+	"Deva": {
+		"hi": strings.Split("है|हैं|के|में|की|और|का|से|यह|था|पर|लिए", "|"),
+		"mr": strings.Split("आहे|आणि|आहेत|हे|व|ते|मध्ये|होते|केले|त्यांनी|म्हणून|नाही", "|"),
+		"ne": strings.Split("छ|र|मा|पनि|गर्न|लागि|छन्|भएको|थियो|गरेको|यो|हुन्छ", "|"),
+	},
+	"Hebr": {
+		"he": strings.Split("של|את|על|הוא|זה|לא|כי|גם|היא|אני|הם|אבל", "|"),
+		"yi": strings.Split("איז|און|דער|די|פון|דאס|ניט|אויף|מיט|נישט|זיך|האט", "|"),
+	},
+}
+
+// trigramRanks is the rank of each trigram in its profile.
+var trigramRanks = func() map[string]map[string]int {
+	ranks := make(map[string]map[string]int, len(trigramProfiles))
+	for lang, profile := range trigramProfiles {
+		ranks[lang] = make(map[string]int, len(profile))
+		for i, trigram := range profile {
+			ranks[lang][trigram] = i
+		}
+	}
+	return ranks
+}()
+
+// normalizeLanguageTag converts the language into a BCP 47 tag, e.g.
+// "EN_us" becomes "en-US". Only the first language is used when there are
+// several of them, like in the Content-Language header. It returns empty
+// string if the language is not valid.
+func normalizeLanguageTag(lang string) string {
+	lang, _, _ = strings.Cut(lang, ",")
+	lang = strings.ReplaceAll(strings.TrimSpace(lang), "_", "-")
+	if lang == "" {
+		return ""
+	}
+
+	tag, err := language.Parse(lang)
+	if err != nil || tag == language.Und {
+		return ""
+	}
+
+	return tag.String()
+}
+
+// detectLanguage detects the language of the text without any external
+// service. The writing system is enough to detect the languages that have
+// their own script, e.g. Japanese and Thai. For the languages written in
+// Latin script, the trigrams of the text are compared with the trigram
+// profiles of the common languages. For the other scripts that are shared
+// by several languages, the language is only returned if it can be told
+// apart from the others, otherwise only the script is returned, e.g.
+// "und-Arab". It returns empty string if the language can't be detected,
+// e.g. the text is too short.
+func detectLanguage(text string) string {
+	scriptCounts := map[string]int{}
+	cyrillicCounts := map[string]int{}
+	nLetters := 0
+	for _, r := range text {
+		if nLetters >= maxDetectionLetters {
+			break
+		}
+
+		if !unicode.IsLetter(r) {
+			continue
+		}
+
+		nLetters++
+		switch {
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			scriptCounts["ja"]++
+		case unicode.Is(unicode.Han, r):
+			scriptCounts["zh"]++
+		case unicode.Is(unicode.Hangul, r):
+			scriptCounts["ko"]++
+		case unicode.Is(unicode.Thai, r):
+			scriptCounts["th"]++
+		case unicode.Is(unicode.Lao, r):
+			scriptCounts["lo"]++
+		case unicode.Is(unicode.Khmer, r):
+			scriptCounts["km"]++
+		case unicode.Is(unicode.Greek, r):
+			scriptCounts["el"]++
+		case unicode.Is(unicode.Hebrew, r):
+			scriptCounts["Hebr"]++
+		case unicode.Is(unicode.Arabic, r):
+			scriptCounts["Arab"]++
+		case unicode.Is(unicode.Devanagari, r):
+			scriptCounts["Deva"]++
+		case unicode.Is(unicode.Cyrillic, r):
+			scriptCounts["cyrillic"]++
+			cyrillicCounts[cyrillicLanguage(r)]++
+		case unicode.Is(unicode.Latin, r):
+			scriptCounts["latin"]++
+		}
+	}
+
+	if nLetters < minDetectionLetters {
+		return ""
+	}
+
+	// Japanese text uses Han chars as well, so a bit of kana is enough.
+	if scriptCounts["ja"] > 0 && scriptCounts["ja"]*10 >= scriptCounts["zh"] {
+		scriptCounts["ja"] += scriptCounts["zh"]
+		scriptCounts["zh"] = 0
+	}
+
+	bestScript, bestCount := "", 0
+	for script, count := range scriptCounts {
+		if count > bestCount || (count == bestCount && script < bestScript) {
+			bestScript, bestCount = script, count
+		}
+	}
+
+	switch bestScript {
+	case "latin":
+		return detectLatinLanguage(text)
+	case "cyrillic":
+		// Cyrillic is used by many languages, so only use the language
+		// that has specific letters in the text.
+		switch {
+		case cyrillicCounts["be"] > 0:
+			return "be"
+		case cyrillicCounts["uk"] > 0 && cyrillicCounts["uk"] >= cyrillicCounts["sr"]:
+			return "uk"
+		case cyrillicCounts["sr"] > 0:
+			return "sr"
+		case cyrillicCounts["ru"] > 0:
+			return "ru"
+		default:
+			return "und-Cyrl"
+		}
+	case "Arab", "Deva", "Hebr":
+		return detectScriptLanguage(text, bestScript)
+	default:
+		return bestScript
+	}
+}
+
+// cyrillicLanguage returns the language that is specific to the Cyrillic
+// letter, or empty string if it's used by several languages. The Russian
+// letters are used by Belarusian as well, so Belarusian is checked first.
+func cyrillicLanguage(r rune) string {
+	switch unicode.ToLower(r) {
+	case 'ў':
+		return "be"
+	case 'і', 'ї', 'є', 'ґ':
+		return "uk"
+	case 'ђ', 'ћ', 'џ', 'љ', 'њ', 'ј':
+		return "sr"
+	case 'ы', 'э', 'ё':
+		return "ru"
+	default:
+		return ""
+	}
+}
+
+// detectScriptLanguage counts the common words of each language in the
+// word profiles of the script. The language is only used if its words are
+// found clearly more often than the words of the other languages,
+// otherwise the script-only tag is returned, e.g. "und-Deva".
+func detectScriptLanguage(text string, script string) string {
+	profiles := scriptWordProfiles[script]
+	hits := make(map[string]int, len(profiles))
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsMark(r)
+	})
+
+	for i, word := range words {
+		if i >= maxDetectionLetters {
+			break
+		}
+
+		for lang, profile := range profiles {
+			for _, common := range profile {
+				if word == common {
+					hits[lang]++
+					break
+				}
+			}
+		}
+	}
+
+	bestLang, bestHits, secondHits := "", 0, 0
+	for lang, count := range hits {
+		switch {
+		case count > bestHits:
+			bestLang, bestHits, secondHits = lang, count, bestHits
+		case count > secondHits:
+			secondHits = count
+		}
+	}
+
+	if bestHits < 2 || bestHits*10 < secondHits*11 {
+		return "und-" + script
+	}
+
+	return bestLang
+}
+
+// detectLatinLanguage compares the trigrams of the text with the trigram
+// profiles. Every trigram that exists in a profile adds a score that is
+// bigger for the more common trigrams in that language. The language is
+// only used if enough trigrams of the text exist in its profile, so text
+// in the languages without profile is not detected.
+func detectLatinLanguage(text string) string {
+	nTrigrams := 0
+	trigramCounts := map[string]int{}
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	}) {
+		runes := []rune(" " + word + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			trigramCounts[string(runes[i:i+3])]++
+			nTrigrams++
+		}
+
+		if nTrigrams >= maxDetectionLetters {
+			break
+		}
+	}
+
+	// Use the languages in sorted order, so ties are resolved consistently
+	languages := make([]string, 0, len(trigramRanks))
+	for lang := range trigramRanks {
+		languages = append(languages, lang)
+	}
+	sort.Strings(languages)
+
+	bestLang, bestScore, bestHits, secondScore := "", 0, 0, 0
+	for _, lang := range languages {
+		score, hits := 0, 0
+		ranks := trigramRanks[lang]
+		for trigram, count := range trigramCounts {
+			if rank, exist := ranks[trigram]; exist {
+				score += count * (len(trigramProfiles[lang]) - rank)
+				hits += count
+			}
+		}
+
+		if score > bestScore {
+			bestLang, bestScore, bestHits, secondScore = lang, score, hits, bestScore
+		} else if score > secondScore {
+			secondScore = score
+		}
+	}
+
+	// The text must be clearly closer to one of the languages
+	if bestHits*100 < nTrigrams*minTrigramCoverage || bestScore*10 < secondScore*11 {
+		return ""
+	}
+
+	return bestLang
+}
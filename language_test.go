@@ -0,0 +1,68 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_normalizeLanguageTag(t *testing.T) {
+	scenarios := map[string]string{
+		"en":           "en",
+		"EN_us":        "en-US",
+		" pt-br ":      "pt-BR",
+		"en-US, fr-CA": "en-US",
+		"zh-hant-tw":   "zh-Hant-TW",
+		"":             "",
+		"not a lang":   "",
+	}
+
+	for lang, expected := range scenarios {
+		if result := normalizeLanguageTag(lang); result != expected {
+			t.Errorf("\n"+
+				"lang : %q\n"+
+				"want : %q\n"+
+				"got  : %q", lang, expected, result)
+		}
+	}
+}
+
+func Test_detectLanguage(t *testing.T) {
+	scenarios := map[string]string{
+		"The quick brown fox jumps over the lazy dog, and then it runs away into the forest with the other animals.":           "en",
+		"Le renard brun rapide saute par-dessus le chien paresseux, puis il s'enfuit dans la forêt avec les autres animaux.":   "fr",
+		"Der schnelle braune Fuchs springt über den faulen Hund, und dann läuft er mit den anderen Tieren in den Wald.":        "de",
+		"El rápido zorro marrón salta sobre el perro perezoso, y luego se escapa al bosque con los otros animales de la casa.": "es",
+		"敏捷的棕色狐狸跳过了那只懒狗，然后和其他动物一起跑进了森林里面，再也没有回来过了。大家都很想念它。":                                                                    "zh",
+		"素早い茶色の狐は怠惰な犬を飛び越えて、それから他の動物たちと一緒に森の中へ走って行きました。":                                                                       "ja",
+		"Быстрая коричневая лиса перепрыгивает через ленивую собаку и убегает в лес вместе с другими животными.":               "ru",
+		"Швидка руда лисиця перестрибує через ледачого собаку і тікає до лісу разом з іншими тваринами.":                       "uk",
+		"สุนัขจิ้งจอกสีน้ำตาลกระโดดข้ามสุนัขขี้เกียจแล้ววิ่งหนีเข้าไปในป่ากับสัตว์อื่นๆ":                                       "th",
+		"قفز الثعلب البني السريع فوق الكلب الكسول، ثم هرب إلى الغابة مع الحيوانات الأخرى في هذا اليوم.":                        "ar",
+		"روباه قهوه‌ای سریع از روی سگ تنبل پرید و سپس با حیوانات دیگر به جنگل فرار کرد که این خیلی جالب است.":                  "fa",
+		"تیز بھوری لومڑی سست کتے کے اوپر سے چھلانگ لگاتی ہے اور پھر دوسرے جانوروں کے ساتھ جنگل میں بھاگ جاتی ہے۔":              "ur",
+		"كتاب قلم مدرسة بيت شجرة سماء نهر جبل بحر شمس قمر نجم طريق مدينة":                                                      "und-Arab",
+		"तेज़ भूरी लोमड़ी आलसी कुत्ते के ऊपर से कूदती है और फिर दूसरे जानवरों के साथ जंगल में भाग जाती है।":                    "hi",
+		"चपळ तपकिरी कोल्हा आळशी कुत्र्यावरून उडी मारतो आणि नंतर इतर प्राण्यांसोबत जंगलात पळून जातो, हे खरे आहे.":               "mr",
+		"कोल्हा कुत्रा जंगल प्राणी नदी पर्वत आकाश सूर्य चंद्र तारा वृक्ष पुष्प समुद्र वारा पाऊस":                               "und-Deva",
+		"השועל החום המהיר קפץ מעל הכלב העצלן, ואז הוא ברח אל היער כי זה היה יום יפה וגם שקט.":                                  "he",
+		"דער גיכער ברוינער פוקס שפרינגט איבער דעם פוילן הונט און דאס איז געווען א שיינער טאג פון זומער.":                       "yi",
+		"Бързата кафява лисица прескача мързеливото куче и бяга в гората заедно с другите животни.":                            "und-Cyrl",
+		"Too short.": "",
+		"Rychlá hnědá liška skáče přes líného psa a potom utíká do lesa spolu s ostatními zvířaty.": "",
+	}
+
+	for text, expected := range scenarios {
+		if result := detectLanguage(text); result != expected {
+			t.Errorf("\n"+
+				"text : %q\n"+
+				"want : %q\n"+
+				"got  : %q", text, expected, result)
+		}
+	}
+
+	// Only the beginning of long text is used
+	longText := strings.Repeat("The quick brown fox jumps over the lazy dog. ", 500)
+	if result := detectLanguage(longText); result != "en" {
+		t.Errorf("long text, want %q got %q", "en", result)
+	}
+}
@@ -0,0 +1,340 @@
+package readability
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+var (
+	rxBylinePrefix    = regexp.MustCompile(`(?i)^\s*(?:(?:written|posted|published|story)\s+)?by\b\s*:?\s*`)
+	rxBylineSeparator = regexp.MustCompile(`(?i)\s*(?:;|&|\band\b)\s*`)
+)
+
+// authorSelector is the selector of elements in the document that describe
+// the author of the article: microdata, rel=author links and hCard.
+const authorSelector = `[itemprop~="author"], a[rel~="author"], .vcard.author, .author .vcard, .h-card.p-author`
+
+// Author is the author of an article.
+type Author struct {
+	Name  string
+	URL   string
+	Image string
+}
+
+// getArticleAuthors returns the authors of the article. The authors are
+// taken from the first of these sources that has any author:
+//
+//  1. `author` in JSON-LD metadata,
+//  2. microdata, rel=author links and hCard in the document,
+//  3. the byline in the metadata (e.g. `meta[name=author]`),
+//  4. the byline found while grabbing the article.
+//
+// The sources after it are only used to complete the URL and image of
+// the authors with the same name. Bylines are split into several authors,
+// so "By John and Jane" will give two authors.
+func (ps *Parser) getArticleAuthors(documentAuthors []Author, metadataByline string) []Author {
+	sources := [][]Author{
+		ps.jsonLdAuthors,
+		documentAuthors,
+		bylineAuthors(metadataByline),
+		bylineAuthors(ps.articleByline),
+	}
+
+	var authors []Author
+	for _, source := range sources {
+		authors = mergeAuthors(authors, source, len(authors) == 0)
+	}
+
+	return authors
+}
+
+// getDocumentAuthors finds the authors that are described by elements in
+// the document. It must be called before the article is grabbed, since the
+// byline will be removed from the document.
+func (ps *Parser) getDocumentAuthors() []Author {
+	var authors []Author
+	nodes := dom.QuerySelectorAll(ps.doc, authorSelector)
+	articleItem := ps.findMicrodataArticle()
+	commentThreads := ps.findCommentThreads()
+
+	isAuthorNode := make(map[*html.Node]struct{})
+	for _, node := range nodes {
+		isAuthorNode[node] = struct{}{}
+	}
+
+	ps.forEachNode(nodes, func(node *html.Node, _ int) {
+		// Nested nodes (e.g. a rel=author link inside a microdata
+		// item) are already handled by their ancestor.
+		for parent := node.Parent; parent != nil; parent = parent.Parent {
+			if _, exist := isAuthorNode[parent]; exist {
+				return
+			}
+		}
+
+		// Authors of other microdata items (e.g. comments) and authors in
+		// comment section are not the authors of the article.
+		if !isArticleItemProp(node, articleItem) {
+			return
+		}
+
+		inComments := ps.someNode(commentThreads, func(thread *html.Node) bool {
+			return !containsNode(thread, articleItem) && containsNode(thread, node)
+		})
+		if inComments {
+			return
+		}
+
+		// Several links to the same profile (e.g. "View my profile")
+		// describe the same author.
+		for _, author := range ps.readAuthorNode(node) {
+			if author.URL != "" && authorURLExists(authors, author.URL) {
+				continue
+			}
+			authors = mergeAuthors(authors, []Author{author}, true)
+		}
+	})
+
+	return authors
+}
+
+// isArticleItemProp checks whether the node belongs to the article, which
+// means its closest microdata item is the article item or one of its
+// ancestors (e.g. WebPage). If the article item is not found, only the
+// nodes that belong to a comment or post item are rejected.
+func isArticleItemProp(node, articleItem *html.Node) bool {
+	var scope *html.Node
+	for parent := node.Parent; parent != nil; parent = parent.Parent {
+		if dom.HasAttribute(parent, "itemscope") {
+			scope = parent
+			break
+		}
+	}
+
+	switch {
+	case scope == nil:
+		return true
+	case articleItem != nil:
+		return containsNode(scope, articleItem)
+	}
+
+	for _, itemType := range strings.Fields(dom.GetAttribute(scope, "itemtype")) {
+		if rxThreadPostTypes.MatchString(microdataName(itemType)) {
+			return false
+		}
+	}
+	return true
+}
+
+// readAuthorNode reads the author described by the node. Plain text that
+// mentions several authors will be split into several authors.
+func (ps *Parser) readAuthorNode(node *html.Node) []Author {
+	var author Author
+	switch {
+	case dom.HasAttribute(node, "itemscope"):
+		author.Name = itemPropValue(node, "name")
+		author.URL = itemPropValue(node, "url")
+		author.Image = itemPropValue(node, "image")
+		if author.Name == "" {
+			author.Name = dom.TextContent(node)
+		}
+
+	case hasAnyClass(node, "vcard", "h-card"):
+		if name := dom.QuerySelector(node, ".fn, .p-name"); name != nil {
+			author.Name = dom.TextContent(name)
+		}
+
+		if link := dom.QuerySelector(node, "a.url[href], a.u-url[href]"); link != nil {
+			author.URL = dom.GetAttribute(link, "href")
+		} else if dom.TagName(node) == "a" {
+			author.URL = dom.GetAttribute(node, "href")
+		}
+
+		if photo := dom.QuerySelector(node, "img.photo[src], img.u-photo[src]"); photo != nil {
+			author.Image = dom.GetAttribute(photo, "src")
+		}
+
+		if author.Name == "" {
+			author.Name = dom.TextContent(node)
+		}
+
+	default:
+		author.Name = dom.TextContent(node)
+		if dom.TagName(node) == "meta" {
+			author.Name = dom.GetAttribute(node, "content")
+		}
+
+		if dom.TagName(node) == "a" {
+			author.URL = dom.GetAttribute(node, "href")
+		} else if links := dom.QuerySelectorAll(node, "a[href]"); len(links) == 1 {
+			author.URL = dom.GetAttribute(links[0], "href")
+		}
+
+		// Without link, the text might be a byline with several authors
+		if author.URL == "" {
+			return bylineAuthors(author.Name)
+		}
+	}
+
+	author.Name = normalizeAuthorName(author.Name)
+	if !ps.isValidByline(author.Name) {
+		return nil
+	}
+
+	author.URL = ps.resolveMetadataURI(author.URL)
+	author.Image = ps.resolveMetadataURI(author.Image)
+	return []Author{author}
+}
+
+// getJSONLDAuthors reads the authors in the `author` value of JSON-LD
+// metadata, which might be a name, a Person object or an array of both.
+func (ps *Parser) getJSONLDAuthors(value interface{}) []Author {
+	var authors []Author
+	switch val := value.(type) {
+	case string:
+		authors = bylineAuthors(val)
+
+	case map[string]interface{}:
+		name, _ := val["name"].(string)
+		author := Author{
+			Name:  normalizeAuthorName(name),
+			URL:   ps.resolveMetadataURI(jsonLDURL(val["url"])),
+			Image: ps.resolveMetadataURI(jsonLDURL(val["image"], "url", "contentUrl")),
+		}
+
+		if author.Name != "" {
+			authors = append(authors, author)
+		}
+
+	case []interface{}:
+		for _, item := range val {
+			authors = mergeAuthors(authors, ps.getJSONLDAuthors(item), true)
+		}
+	}
+
+	return authors
+}
+
+// bylineAuthors splits a byline like "By John Doe and Jane Doe" into
+// several authors that only have a name.
+func bylineAuthors(byline string) []Author {
+	byline = normalizeAuthorName(byline)
+	if byline == "" || charCount(byline) >= 100 {
+		return nil
+	}
+
+	var authors []Author
+	for _, part := range rxBylineSeparator.Split(byline, -1) {
+		for _, name := range splitBylineCommas(part) {
+			if name = normalizeAuthorName(name); name != "" {
+				authors = mergeAuthors(authors, []Author{{Name: name}}, true)
+			}
+		}
+	}
+
+	return authors
+}
+
+// splitBylineCommas splits the names that are separated by commas, like
+// "John Doe, Jane Doe". Comma is also used inside a name, like "Doe, Jr."
+// or "Doe, John", so it's only split when each part has at least two words.
+func splitBylineCommas(byline string) []string {
+	parts := strings.Split(byline, ",")
+	for _, part := range parts {
+		if len(strings.Fields(part)) < 2 {
+			return []string{byline}
+		}
+	}
+	return parts
+}
+
+// mergeAuthors merges the authors in source into authors. Authors with the
+// same name are merged into one, completing its missing URL and image. If
+// appendNew is false, authors that don't exist yet are ignored.
+func mergeAuthors(authors []Author, source []Author, appendNew bool) []Author {
+	for _, author := range source {
+		key := strings.ToLower(author.Name)
+
+		found := false
+		for i := range authors {
+			if strings.ToLower(authors[i].Name) != key {
+				continue
+			}
+
+			found = true
+			authors[i].URL = strOr(authors[i].URL, author.URL)
+			authors[i].Image = strOr(authors[i].Image, author.Image)
+			break
+		}
+
+		if !found && appendNew {
+			authors = append(authors, author)
+		}
+	}
+
+	return authors
+}
+
+// authorURLExists checks if any of the authors has the specified URL.
+func authorURLExists(authors []Author, url string) bool {
+	for _, author := range authors {
+		if author.URL == url {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeAuthorName collapses the whitespaces in the name and removes
+// the "By" prefix that is commonly used in byline.
+func normalizeAuthorName(name string) string {
+	name = strings.Join(strings.Fields(name), " ")
+	name = rxBylinePrefix.ReplaceAllString(name, "")
+	return strings.Trim(name, " ,;:|-–—")
+}
+
+// itemPropValue returns the value of the first descendant of the microdata
+// item whose itemprop is prop.
+func itemPropValue(item *html.Node, prop string) string {
+	node := dom.QuerySelector(item, `[itemprop~="`+prop+`"]`)
+	if node == nil {
+		return ""
+	}
+
+	return itemPropNodeValue(node)
+}
+
+// itemPropNodeValue returns the value of a microdata property, which might be
+// kept in an attribute or in the text, depending on the tag of the node.
+func itemPropNodeValue(node *html.Node) string {
+	switch dom.TagName(node) {
+	case "meta":
+		return dom.GetAttribute(node, "content")
+	case "a", "link", "area":
+		return dom.GetAttribute(node, "href")
+	case "img", "audio", "video", "source", "embed", "iframe", "track":
+		return dom.GetAttribute(node, "src")
+	case "time":
+		if dom.HasAttribute(node, "datetime") {
+			return dom.GetAttribute(node, "datetime")
+		}
+	}
+
+	return dom.TextContent(node)
+}
+
+// hasAnyClass checks if the node has any of the specified classes.
+func hasAnyClass(node *html.Node, classes ...string) bool {
+	nodeClasses := strings.Fields(dom.ClassName(node))
+	for _, class := range classes {
+		for _, nodeClass := range nodeClasses {
+			if nodeClass == class {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,94 @@
+package readability
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func Test_bylineAuthors(t *testing.T) {
+	scenarios := map[string]string{
+		"John Doe":                              "John Doe",
+		"By John Doe and Jane Doe":              "John Doe|Jane Doe",
+		"Written by: Ann Lee, Bob Stone & Carl": "Ann Lee|Bob Stone|Carl",
+		"By John Doe, Jr.":                      "John Doe, Jr.",
+		"Doe, John and Roe, Jane":               "Doe, John|Roe, Jane",
+		"by Andy Anderson; andy ANDERSON":       "Andy Anderson",
+		"Byron Smith":                           "Byron Smith",
+		"   ":                                   "",
+	}
+
+	for byline, expected := range scenarios {
+		var names []string
+		for _, author := range bylineAuthors(byline) {
+			names = append(names, author.Name)
+		}
+
+		if result := strings.Join(names, "|"); result != expected {
+			t.Errorf("\n"+
+				"byline : %s\n"+
+				"want   : %s\n"+
+				"got    : %s", byline, expected, result)
+		}
+	}
+}
+
+func Test_articleAuthors(t *testing.T) {
+	content := loremParagraph(20)
+	makePage := func(head, byline string) string {
+		return "<html><head>" + head + "</head><body><article>" +
+			byline + content + content + "</article></body></html>"
+	}
+
+	scenarios := map[string][]Author{
+		// JSON-LD authors, completed by rel=author link
+		makePage(`<script type="application/ld+json">{"@context":"https://schema.org","@type":"Article",
+			"author":[{"@type":"Person","name":"John Doe","image":{"url":"/john.jpg"}},"Jane Doe"]}</script>`,
+			`<p>By <a rel="author" href="/author/john">John Doe</a> and Jane Doe</p>`): {
+			{Name: "John Doe", URL: "http://fakehost/author/john", Image: "http://fakehost/john.jpg"},
+			{Name: "Jane Doe"},
+		},
+
+		// Microdata author
+		makePage(``, `<div itemprop="author" itemscope itemtype="https://schema.org/Person">
+			<img itemprop="image" src="/ann.png"><a itemprop="url" href="/ann"><span itemprop="name">Ann</span></a></div>`): {
+			{Name: "Ann", URL: "http://fakehost/ann", Image: "http://fakehost/ann.png"},
+		},
+
+		// hCard author and a second link to the same profile
+		makePage(``, `<p class="author vcard"><a class="url fn" href="/bob">Bob</a></p>
+			<a rel="author" href="/bob">View my complete profile</a>`): {
+			{Name: "Bob", URL: "http://fakehost/bob"},
+		},
+
+		// Authors of comments are ignored
+		makePage(``, `<p class="author vcard"><span class="fn">Ann</span></p>
+			<div itemscope itemtype="https://schema.org/Comment"><span itemprop="author">Troll</span>
+			<p itemprop="text">First!</p></div>
+			<ol class="comment-list">
+			<li class="comment"><a rel="author" href="/u/one">Commenter One</a> Nice article.</li>
+			<li class="comment"><a rel="author" href="/u/two">Commenter Two</a> Thanks.</li></ol>`): {
+			{Name: "Ann"},
+		},
+
+		// Meta author is split
+		makePage(`<meta name="author" content="By Ann &amp; Bob">`, ``): {
+			{Name: "Ann"},
+			{Name: "Bob"},
+		},
+	}
+
+	for source, expected := range scenarios {
+		article, err := FromReader(strings.NewReader(source), fakeHostURL)
+		if err != nil {
+			t.Fatalf("failed to parse: %v", err)
+		}
+
+		if result, want := fmt.Sprintf("%+v", article.Authors), fmt.Sprintf("%+v", expected); result != want {
+			t.Errorf("\n"+
+				"source : %s\n"+
+				"want   : %s\n"+
+				"got    : %s", source, want, result)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package readability
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"unicode/utf8"
+
+	"github.com/go-shiori/dom"
+	"github.com/gogs/chardet"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// parseHTML parses the input as HTML document while converting its character
+// encoding into UTF-8. The encoding is picked in this order :
+//
+//  1. `Parser.ForceCharset`,
+//  2. the byte order mark in the input,
+//  3. charsetHint, which normally is the charset declared by the server,
+//  4. the encoding detected from the content, including its meta tags.
+//
+// It returns the parsed document and the name of the encoding that is used.
+func (ps *Parser) parseHTML(input io.Reader, charsetHint string) (*html.Node, string, error) {
+	content, err := io.ReadAll(input)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var enc encoding.Encoding
+	var encName string
+	if ps.ForceCharset != "" {
+		enc, encName = charset.Lookup(ps.ForceCharset)
+		if enc == nil {
+			return nil, "", fmt.Errorf("unknown charset %q", ps.ForceCharset)
+		}
+	}
+
+	if enc == nil && charsetHint != "" {
+		if _, hintName := charset.Lookup(charsetHint); hintName != "" {
+			// DetermineEncoding prefers the byte order mark over the hint
+			enc, encName, _ = charset.DetermineEncoding(content, "text/html; charset="+hintName)
+		} else {
+			ps.traceInfo("ignoring unknown charset hint %q", charsetHint)
+		}
+	}
+
+	if enc == nil {
+		enc, encName = detectCharset(content)
+	}
+
+	// Like `dom.Parse`, normalize the text into NFC and remove soft hyphens
+	softHyphen := runes.Predicate(func(r rune) bool { return r == '\u00AD' })
+	transformer := transform.Chain(enc.NewDecoder(), norm.NFD, runes.Remove(softHyphen), norm.NFC)
+
+	r := transform.NewReader(bytes.NewReader(content), transformer)
+	doc, err := dom.FastParse(r)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return doc, encName, nil
+}
+
+// detectCharset detects the character encoding of the HTML content. Content
+// that is valid UTF-8 is always treated as UTF-8, since the detector tends
+// to report plain ASCII as ISO-8859-1. Otherwise the charset declared in
+// the meta tags is used, then the encoding guessed by the detector. If it's
+// unknown, UTF-8 will be used.
+func detectCharset(content []byte) (encoding.Encoding, string) {
+	if utf8.Valid(content) {
+		return unicode.UTF8, "utf-8"
+	}
+
+	// Without any meta tag, DetermineEncoding falls back to windows-1252,
+	// which is only a guess, so the detector is used instead.
+	if enc, name, _ := charset.DetermineEncoding(content, "text/html"); name != "windows-1252" && name != "utf-8" {
+		return enc, name
+	}
+
+	if res, err := chardet.NewHtmlDetector().DetectBest(content); err == nil {
+		if enc, name := charset.Lookup(res.Charset); enc != nil {
+			return enc, name
+		}
+	}
+
+	return unicode.UTF8, "utf-8"
+}
+
+// contentTypeCharset returns the charset parameter of the Content-Type header.
+func contentTypeCharset(contentType string) string {
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+
+	return params["charset"]
+}
@@ -0,0 +1,87 @@
+package readability
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+)
+
+func Test_parseHTML(t *testing.T) {
+	encode := func(s string, enc interface{ Bytes([]byte) ([]byte, error) }) []byte {
+		b, err := enc.Bytes([]byte(s))
+		if err != nil {
+			t.Fatalf("failed to encode %q: %v", s, err)
+		}
+		return b
+	}
+
+	page := func(metaCharset, title string) string {
+		return `<html><head><meta charset="` + metaCharset + `"><title>` + title + `</title></head><body></body></html>`
+	}
+
+	scenarios := []struct {
+		name         string
+		input        []byte
+		hint         string
+		force        string
+		wantTitle    string
+		wantEncoding string
+	}{{
+		name:         "hint overrides misleading meta",
+		input:        encode(page("utf-8", "日本語のタイトル"), japanese.ShiftJIS.NewEncoder()),
+		hint:         "Shift_JIS",
+		wantTitle:    "日本語のタイトル",
+		wantEncoding: "shift_jis",
+	}, {
+		name:         "byte order mark overrides hint",
+		input:        append([]byte("\xEF\xBB\xBF"), page("utf-8", "Привет")...),
+		hint:         "windows-1251",
+		wantTitle:    "Привет",
+		wantEncoding: "utf-8",
+	}, {
+		name:         "forced encoding",
+		input:        encode(page("utf-8", "Привет"), charmap.Windows1251.NewEncoder()),
+		force:        "cp1251",
+		wantTitle:    "Привет",
+		wantEncoding: "windows-1251",
+	}, {
+		name:         "charset from meta tag",
+		input:        encode(page("windows-1251", "Привет"), charmap.Windows1251.NewEncoder()),
+		wantTitle:    "Привет",
+		wantEncoding: "windows-1251",
+	}, {
+		name: "charset from http-equiv meta tag",
+		input: encode(`<html><head><meta http-equiv="Content-Type" content="text/html; charset=euc-jp">`+
+			`<title>日本語</title></head><body></body></html>`, japanese.EUCJP.NewEncoder()),
+		wantTitle:    "日本語",
+		wantEncoding: "euc-jp",
+	}, {
+		name:         "unknown hint is ignored",
+		input:        []byte(page("utf-8", "Hello")),
+		hint:         "not-a-charset",
+		wantTitle:    "Hello",
+		wantEncoding: "utf-8",
+	}}
+
+	for _, scenario := range scenarios {
+		parser := NewParser()
+		parser.ForceCharset = scenario.force
+
+		doc, encoding, err := parser.parseHTML(bytes.NewReader(scenario.input), scenario.hint)
+		if err != nil {
+			t.Errorf("%s: failed to parse: %v", scenario.name, err)
+			continue
+		}
+
+		title := dom.TextContent(dom.GetElementsByTagName(doc, "title")[0])
+		if title != scenario.wantTitle || encoding != scenario.wantEncoding {
+			t.Errorf("\n"+
+				"scenario : %s\n"+
+				"want     : %q (%s)\n"+
+				"got      : %q (%s)", scenario.name, scenario.wantTitle, scenario.wantEncoding, title, encoding)
+		}
+	}
+}
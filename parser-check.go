@@ -6,14 +6,13 @@ import (
 	"strings"
 
 	"github.com/go-shiori/dom"
-	"github.com/go-shiori/go-readability/internal/re2go"
 	"golang.org/x/net/html"
 )
 
 // Check checks whether the input is readable without parsing the whole thing.
 func (ps *Parser) Check(input io.Reader) bool {
 	// Parse input
-	doc, err := dom.Parse(input)
+	doc, _, err := ps.parseHTML(input, ps.CharsetHint)
 	if err != nil {
 		return false
 	}
@@ -59,8 +58,7 @@ func (ps *Parser) CheckDocument(doc *html.Node) bool {
 		}
 
 		matchString := dom.ClassName(node) + " " + dom.ID(node)
-		if re2go.IsUnlikelyCandidates(matchString) &&
-			!re2go.MaybeItsACandidate(matchString) {
+		if ps.isUnlikelyCandidate(matchString) {
 			return false
 		}
 
@@ -0,0 +1,371 @@
+package readability
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+var (
+	rxCommentThread    = regexp.MustCompile(`(?i)comment|disqus|replies|discussion`)
+	rxCommentItem      = regexp.MustCompile(`(?i)comment|reply|post|message`)
+	rxCommentAuthor    = regexp.MustCompile(`(?i)author|user|nick|name|\bfn\b`)
+	rxCommentDate      = regexp.MustCompile(`(?i)date|time|publish`)
+	rxCommentContent   = regexp.MustCompile(`(?i)content|body|text|message`)
+	rxCommentForm      = regexp.MustCompile(`(?i)respond|(?:^|[\s_-])form(?:$|[\s_-])`)
+	rxJsonLdComment    = regexp.MustCompile(`^(Comment|Answer)$`)
+	rxJsonLdDiscussion = regexp.MustCompile(`^(DiscussionForumPosting|SocialMediaPosting)$`)
+)
+
+// Comment is a comment of the article, e.g. from a blog comment section.
+type Comment struct {
+	Author        Author
+	PublishedTime *time.Time
+	Content       string
+	TextContent   string
+	// Depth is the nesting level of the comment, starting from 0 for the
+	// comments that reply to the article itself.
+	Depth int
+}
+
+// getJSONLDComments finds the comments that are declared in JSON-LD, as
+// Schema.org `Comment` objects. It must be called before the scripts are
+// removed from the document.
+func (ps *Parser) getJSONLDComments() []Comment {
+	var comments []Comment
+	scripts := dom.QuerySelectorAll(ps.doc, `script[type="application/ld+json"]`)
+	ps.forEachNode(scripts, func(jsonLdElement *html.Node, _ int) {
+		content := rxCDATA.ReplaceAllString(dom.TextContent(jsonLdElement), "")
+
+		var root interface{}
+		if err := json.Unmarshal([]byte(content), &root); err != nil {
+			return
+		}
+
+		comments = append(comments, ps.findJSONLDComments(root, 0)...)
+	})
+
+	return comments
+}
+
+// findJSONLDComments walks through the JSON-LD value and returns every
+// `Comment` in it. Comments that are nested inside another comment, or
+// inside a discussion post, are treated as its replies.
+func (ps *Parser) findJSONLDComments(value interface{}, depth int) []Comment {
+	var comments []Comment
+	switch val := value.(type) {
+	case []interface{}:
+		for _, item := range val {
+			comments = append(comments, ps.findJSONLDComments(item, depth)...)
+		}
+
+	case map[string]interface{}:
+		jsonLDType, _ := val["@type"].(string)
+		childDepth := depth
+		if rxJsonLdComment.MatchString(jsonLDType) {
+			text, _ := val["text"].(string)
+			text = strings.TrimSpace(text)
+
+			var author Author
+			if authors := ps.getJSONLDAuthors(val["author"]); len(authors) > 0 {
+				author = authors[0]
+			}
+
+			dateStr, _ := val["dateCreated"].(string)
+			if dateStr == "" {
+				dateStr, _ = val["datePublished"].(string)
+			}
+
+			var publishedTime *time.Time
+			if dateStr != "" {
+				publishedTime = ps.getParsedDate(dateStr)
+			}
+
+			paragraph := dom.CreateElement("p")
+			dom.SetTextContent(paragraph, text)
+
+			comments = append(comments, Comment{
+				Author:        author,
+				PublishedTime: publishedTime,
+				Content:       dom.OuterHTML(paragraph),
+				TextContent:   text,
+				Depth:         depth,
+			})
+			childDepth = depth + 1
+		} else if rxJsonLdDiscussion.MatchString(jsonLDType) {
+			// The comments of a discussion post reply to the post, just
+			// like the comments of an article.
+			childDepth = depth
+		}
+
+		// Sort the keys, so the comments are always in the same order
+		keys := make([]string, 0, len(val))
+		for key := range val {
+			if key != "@context" && key != "author" {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			comments = append(comments, ps.findJSONLDComments(val[key], childDepth)...)
+		}
+	}
+
+	return comments
+}
+
+// getDocumentComments finds the comment thread in the document using the
+// class and id hints, then returns its comments. The comments are the
+// elements with repeated structure inside the thread, and replies are
+// the comments that are nested inside another comment.
+func (ps *Parser) getDocumentComments() []Comment {
+	for _, thread := range ps.findCommentThreads() {
+		items := ps.findCommentItems(thread)
+		if len(items) == 0 {
+			continue
+		}
+
+		var comments []Comment
+		for _, item := range items {
+			depth := 0
+			for _, ancestor := range ps.getNodeAncestors(item, 0) {
+				if ancestor == thread {
+					break
+				}
+
+				if indexOfNode(items, ancestor) != -1 {
+					depth++
+				}
+			}
+
+			if post, _, ok := ps.readPostNode(item); ok {
+				comments = append(comments, Comment{
+					Author:        post.Author,
+					PublishedTime: post.PublishedTime,
+					Content:       post.Content,
+					TextContent:   post.TextContent,
+					Depth:         depth,
+				})
+			}
+		}
+
+		if len(comments) > 0 {
+			return comments
+		}
+	}
+
+	return nil
+}
+
+// findCommentThreads returns the outermost visible elements whose class or
+// id hints that they contain the comment thread. The reply forms are not
+// comment threads, even though their class usually mentions comments.
+func (ps *Parser) findCommentThreads() []*html.Node {
+	var threads []*html.Node
+	for _, node := range dom.GetElementsByTagName(ps.doc, "*") {
+		if !ps.isProbablyVisible(node) || isCommentForm(node) {
+			continue
+		}
+
+		matchString := dom.ClassName(node) + " " + dom.ID(node)
+		if !rxCommentThread.MatchString(matchString) {
+			continue
+		}
+
+		nested := ps.someNode(threads, func(thread *html.Node) bool {
+			return containsNode(thread, node)
+		})
+
+		if !nested && strings.TrimSpace(dom.TextContent(node)) != "" {
+			threads = append(threads, node)
+		}
+	}
+
+	return threads
+}
+
+// findCommentItems finds the repeated siblings that look like comments
+// inside the thread. The elements marked as microdata `Comment` or `Answer`
+// are used if there are any. Otherwise the group whose class hints at
+// comments is preferred, then the biggest group. Once the group is found,
+// every element in the thread with the same signature is returned,
+// including the nested ones. A group with a single element is not enough
+// to be a comment thread.
+func (ps *Parser) findCommentItems(thread *html.Node) []*html.Node {
+	var microdataItems []*html.Node
+	for _, node := range dom.QuerySelectorAll(thread, "[itemscope][itemtype]") {
+		if hasMicrodataType(node, rxJsonLdComment) && !isCommentForm(node) {
+			microdataItems = append(microdataItems, node)
+		}
+	}
+
+	if len(microdataItems) > 0 {
+		return microdataItems
+	}
+
+	bestSignature := ""
+	bestCount := 0
+	bestHinted := false
+
+	parents := append([]*html.Node{thread}, dom.GetElementsByTagName(thread, "*")...)
+	for _, parent := range parents {
+		if isCommentForm(parent) {
+			continue
+		}
+
+		counts := map[string]int{}
+		var signatures []string
+		for _, child := range dom.Children(parent) {
+			if isCommentForm(child) {
+				continue
+			}
+
+			signature := commentSignature(child)
+			if counts[signature] == 0 {
+				signatures = append(signatures, signature)
+			}
+			counts[signature]++
+		}
+
+		for _, signature := range signatures {
+			count := counts[signature]
+			hinted := rxCommentItem.MatchString(signature)
+			if count < 2 && !hinted {
+				continue
+			}
+
+			if (hinted && !bestHinted) || (hinted == bestHinted && count > bestCount) {
+				bestSignature, bestCount, bestHinted = signature, count, hinted
+			}
+		}
+	}
+
+	if bestSignature == "" {
+		return nil
+	}
+
+	var items []*html.Node
+	for _, node := range dom.GetElementsByTagName(thread, "*") {
+		if commentSignature(node) == bestSignature && !isCommentForm(node) {
+			items = append(items, node)
+		}
+	}
+
+	if len(items) < 2 {
+		return nil
+	}
+
+	return items
+}
+
+// isCommentForm checks whether the node is part of a form, e.g. the form
+// to reply to the article, or its class or id hints that it is.
+func isCommentForm(node *html.Node) bool {
+	for ; node != nil && node.Type == html.ElementNode; node = node.Parent {
+		switch dom.TagName(node) {
+		case "form", "input", "textarea", "select", "button":
+			return true
+		}
+
+		if rxCommentForm.MatchString(dom.ClassName(node) + " " + dom.ID(node)) {
+			return true
+		}
+	}
+	return false
+}
+
+// commentSignature returns the tag name and the first class of the node,
+// which is used to find elements with the same structure.
+func commentSignature(node *html.Node) string {
+	signature := dom.TagName(node)
+	if classes := strings.Fields(dom.ClassName(node)); len(classes) > 0 {
+		signature += "." + classes[0]
+	}
+	return signature
+}
+
+// containsNode checks whether the node is the ancestor itself or one of
+// its descendants.
+func containsNode(ancestor, node *html.Node) bool {
+	for ; node != nil; node = node.Parent {
+		if node == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
+// readPostNode reads the author, date and content of a post or comment
+// node, ignoring the replies nested inside it, i.e. the nested nodes with
+// the same signature and the nested microdata posts. It also returns the
+// cleaned copy of the content node.
+func (ps *Parser) readPostNode(item *html.Node) (Post, *html.Node, bool) {
+	// Copy the node without its replies
+	clone := dom.Clone(item, true)
+	for _, node := range dom.GetElementsByTagName(clone, "*") {
+		if node.Parent != nil && (commentSignature(node) == commentSignature(item) || hasMicrodataType(node, rxThreadPostTypes)) {
+			node.Parent.RemoveChild(node)
+		}
+	}
+
+	var author Author
+	var authorNode, dateNode, contentNode *html.Node
+	var publishedTime *time.Time
+	for _, node := range dom.GetElementsByTagName(clone, "*") {
+		matchString := dom.ClassName(node) + " " + dom.ID(node) + " " + dom.GetAttribute(node, "itemprop")
+		switch {
+		case authorNode == nil && rxCommentAuthor.MatchString(matchString) && ps.isValidByline(dom.TextContent(node)):
+			authorNode = node
+			author.Name = normalizeAuthorName(ps.getInnerText(node, true))
+			if link := dom.QuerySelector(node, "a[href]"); link != nil {
+				author.URL = ps.resolveURI(dom.GetAttribute(link, "href"))
+			} else if dom.TagName(node) == "a" {
+				author.URL = ps.resolveURI(dom.GetAttribute(node, "href"))
+			}
+
+		case dateNode == nil && (dom.TagName(node) == "time" || dom.HasAttribute(node, "datetime") ||
+			rxCommentDate.MatchString(matchString)):
+			dateNode = node
+			dateStr := strOr(dom.GetAttribute(node, "datetime"), ps.getInnerText(node, true))
+			publishedTime = ps.getParsedDate(dateStr)
+
+		case contentNode == nil && rxCommentContent.MatchString(matchString) && !rxCommentAuthor.MatchString(matchString):
+			contentNode = node
+		}
+	}
+
+	// Without a specific content node, use the whole comment except its
+	// author and date.
+	if contentNode == nil || containsNode(contentNode, authorNode) || containsNode(contentNode, dateNode) {
+		contentNode = clone
+		for _, node := range []*html.Node{authorNode, dateNode} {
+			if node != nil && node.Parent != nil && node != clone {
+				node.Parent.RemoveChild(node)
+			}
+		}
+	}
+
+	ps.removeScripts(contentNode)
+	ps.fixRelativeURIs(contentNode)
+	if !ps.KeepClasses {
+		ps.cleanClasses(contentNode)
+	}
+
+	textContent := ps.getInnerText(contentNode, true)
+	if textContent == "" {
+		return Post{}, nil, false
+	}
+
+	return Post{
+		Author:        author,
+		PublishedTime: publishedTime,
+		Content:       strings.TrimSpace(dom.InnerHTML(contentNode)),
+		TextContent:   textContent,
+	}, contentNode, true
+}
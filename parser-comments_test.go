@@ -0,0 +1,150 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_ExtractComments(t *testing.T) {
+	content := loremParagraph(20)
+	source := `<html><body><article>` + content + `</article>` +
+		`<section id="comments"><ol class="comment-list">` +
+		`<li class="comment"><div class="comment-author"><a href="/u/alice">Alice</a></div>` +
+		`<time datetime="2024-05-01T10:00:00Z">May 1</time>` +
+		`<div class="comment-content"><p>First!</p></div>` +
+		`<ol class="children"><li class="comment"><div class="comment-author">Bob</div>` +
+		`<div class="comment-content"><p>Reply to Alice</p></div></li></ol></li>` +
+		`<li class="comment"><div class="comment-author">Carol</div>` +
+		`<div class="comment-content"><p>Nice <a href="/link">article</a></p></div></li>` +
+		`</ol></section></body></html>`
+
+	// Comments are not extracted by default
+	parser := NewParser()
+	article, err := parser.Parse(strings.NewReader(source), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if len(article.Comments) != 0 {
+		t.Fatalf("comments are extracted by default: %+v", article.Comments)
+	}
+
+	parser.ExtractComments = true
+	article, err = parser.Parse(strings.NewReader(source), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	expected := []struct {
+		author string
+		text   string
+		depth  int
+	}{
+		{"Alice", "First!", 0},
+		{"Bob", "Reply to Alice", 1},
+		{"Carol", "Nice article", 0},
+	}
+
+	if len(article.Comments) != len(expected) {
+		t.Fatalf("want %d comments, got %+v", len(expected), article.Comments)
+	}
+
+	for i, exp := range expected {
+		comment := article.Comments[i]
+		if comment.Author.Name != exp.author || comment.TextContent != exp.text || comment.Depth != exp.depth {
+			t.Errorf("\n"+
+				"want : %s %q at depth %d\n"+
+				"got  : %s %q at depth %d",
+				exp.author, exp.text, exp.depth,
+				comment.Author.Name, comment.TextContent, comment.Depth)
+		}
+	}
+
+	first := article.Comments[0]
+	if first.Author.URL != "http://fakehost/u/alice" || first.PublishedTime == nil || first.PublishedTime.Year() != 2024 {
+		t.Errorf("unexpected author or date of first comment: %+v", first)
+	}
+
+	if !strings.Contains(article.Comments[2].Content, `href="http://fakehost/link"`) {
+		t.Errorf("relative URI is not fixed in comment: %s", article.Comments[2].Content)
+	}
+
+	if strings.Contains(article.TextContent, "First!") {
+		t.Errorf("comments are part of the article content")
+	}
+}
+
+func Test_getJSONLDComments(t *testing.T) {
+	source := `<html><head><script type="application/ld+json">{
+		"@context": "https://schema.org",
+		"@type": "DiscussionForumPosting",
+		"headline": "Question",
+		"comment": [{
+			"@type": "Comment",
+			"text": "Top level <b>comment</b>",
+			"author": {"@type": "Person", "name": "Alice"},
+			"dateCreated": "2024-05-01T10:00:00Z",
+			"comment": {"@type": "Comment", "text": "Nested reply", "author": "Bob"}
+		}]
+	}</script></head><body><div class="comments"><p>Ignored</p></div></body></html>`
+
+	parser := NewParser()
+	parser.ExtractComments = true
+	article, err := parser.Parse(strings.NewReader(source), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if len(article.Comments) != 2 {
+		t.Fatalf("want 2 comments, got %+v", article.Comments)
+	}
+
+	top, reply := article.Comments[0], article.Comments[1]
+	if top.Author.Name != "Alice" || top.Depth != 0 || top.PublishedTime == nil ||
+		top.Content != "<p>Top level &lt;b&gt;comment&lt;/b&gt;</p>" {
+		t.Errorf("unexpected top level comment: %+v", top)
+	}
+
+	if reply.Author.Name != "Bob" || reply.Depth != 1 || reply.TextContent != "Nested reply" {
+		t.Errorf("unexpected reply: %+v", reply)
+	}
+}
+
+func Test_commentFalsePositives(t *testing.T) {
+	content := loremParagraph(20)
+	scenarios := map[string]int{
+		// WordPress reply form without any comment
+		`<div id="respond" class="comment-respond">` +
+			`<h3 class="comment-reply-title">Leave a Reply</h3>` +
+			`<form action="/wp-comments-post.php" method="post" id="commentform" class="comment-form">` +
+			`<p class="comment-notes">Your email address will not be published.</p>` +
+			`<p class="comment-form-comment"><label for="comment">Comment</label><textarea id="comment" name="comment"></textarea></p>` +
+			`<p class="comment-form-author"><label for="author">Name</label><input id="author" name="author"></p>` +
+			`<p class="comment-form-email"><label for="email">Email</label><input id="email" name="email"></p>` +
+			`<p class="form-submit"><input type="submit" value="Post Comment"></p>` +
+			`</form></div>`: 0,
+		// A single element is not a comment thread
+		`<div class="comments"><div class="comment-count"><span class="comment-author">Nobody</span> commented yet</div></div>`: 0,
+		// Unless it's marked as a comment by microdata
+		`<div class="comments"><div itemscope itemtype="https://schema.org/Comment">` +
+			`<span itemprop="author">Alice</span><p itemprop="text">The only comment</p></div></div>`: 1,
+	}
+
+	for thread, expected := range scenarios {
+		source := `<html><body><article>` + content + `</article>` + thread + `</body></html>`
+
+		parser := NewParser()
+		parser.ExtractComments = true
+		article, err := parser.Parse(strings.NewReader(source), fakeHostURL)
+		if err != nil {
+			t.Fatalf("failed to parse: %v", err)
+		}
+
+		if len(article.Comments) != expected {
+			t.Errorf("\n"+
+				"thread : %s\n"+
+				"want   : %d comments\n"+
+				"got    : %+v", thread, expected, article.Comments)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package readability
+
+import (
+	"context"
+	"fmt"
+	"io"
+	nurl "net/url"
+	"strconv"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+const (
+	// explainIDAttr is the attribute used to find the original node of
+	// the nodes reported in the trace events.
+	explainIDAttr = "data-readability-explain-id"
+
+	// traceGrabbingArticle and traceAttemptFinished are the reasons of the
+	// TraceInfo events that surround each attempt of `grabArticle`, no
+	// matter whether the attempt succeeds or not.
+	traceGrabbingArticle = "grabbing article"
+	traceAttemptFinished = "attempt finished"
+)
+
+// explainStyle is the stylesheet injected into the explained document.
+const explainStyle = `
+[data-readability-removed] {
+  outline: 2px dashed #d32f2f !important;
+  background-color: rgba(211, 47, 47, 0.08) !important;
+  opacity: 0.6;
+}
+[data-readability-removed="hidden node"] {
+  display: block !important;
+  visibility: visible !important;
+}
+[data-readability-selected] {
+  outline: 3px solid #388e3c !important;
+}
+[data-readability-converted] {
+  outline: 1px dotted #1976d2;
+}
+[data-readability-removed]::before,
+[data-readability-selected]::before {
+  display: block;
+  font: bold 11px/1.4 monospace;
+  padding: 1px 4px;
+  color: #fff;
+}
+[data-readability-removed]::before {
+  content: "removed: " attr(data-readability-removed);
+  background-color: #d32f2f;
+}
+[data-readability-selected]::before {
+  content: attr(data-readability-selected) " (score " attr(data-readability-score) ")";
+  background-color: #388e3c;
+}
+`
+
+// Explain is like `Parse`, but instead of the article it returns the
+// original document where every element is annotated with the decisions
+// made by the parser. See `ExplainDocument` for the annotations.
+func (ps *Parser) Explain(input io.Reader, pageURL *nurl.URL) (*html.Node, error) {
+	doc, _, err := ps.parseHTML(input, ps.CharsetHint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse input: %w", err)
+	}
+
+	return ps.ExplainDocument(doc, pageURL)
+}
+
+// ExplainDocument parses the document, then returns a copy of it where
+// every element in body is annotated with the following attributes:
+//
+//   - data-readability-score: the last content score of the element
+//   - data-readability-class-weight: the weight of its class and id
+//   - data-readability-link-density: its link density
+//   - data-readability-removed: the rule that removed the element
+//   - data-readability-selected: why it's selected as the article content
+//   - data-readability-skipped: why it's ignored by a decision
+//   - data-readability-converted: the tag it's converted into
+//
+// A stylesheet is injected so the decisions are visible when the document
+// is opened in browser, while the scripts are blocked using CSP. When the
+// parser needs several attempts to find the article, only the decisions
+// of the attempt that is used for the article are shown.
+func (ps *Parser) ExplainDocument(doc *html.Node, pageURL *nurl.URL) (*html.Node, error) {
+	explained := dom.Clone(doc, true)
+
+	// Number every element, so the nodes in trace events can be found
+	// even though the parser works on its own copy of the document.
+	elements := map[string]*html.Node{}
+	for i, element := range dom.GetElementsByTagName(explained, "*") {
+		id := strconv.Itoa(i)
+		dom.SetAttribute(element, explainIDAttr, id)
+		elements[id] = element
+	}
+
+	// Group the events by attempt, since each attempt works on a new
+	// copy of the document. Events before the first attempt and after
+	// the last one are kept separately.
+	var before, after []TraceEvent
+	var attempts [][]TraceEvent
+	inAttempt := false
+
+	// Next pages are not part of the explained document
+	parser := ps.newOptionsParser()
+	parser.MaxPages = 0
+	parser.Tracer = TracerFunc(func(event TraceEvent) {
+		ps.trace(event)
+
+		switch {
+		case event.Action == TraceInfo && event.Reason == traceGrabbingArticle:
+			attempts = append(attempts, nil)
+			inAttempt = true
+		case event.Action == TraceInfo && event.Reason == traceAttemptFinished:
+			inAttempt = false
+		case inAttempt:
+			attempts[len(attempts)-1] = append(attempts[len(attempts)-1], event)
+		case len(attempts) == 0:
+			before = append(before, event)
+		default:
+			after = append(after, event)
+		}
+	})
+
+	article, err := parser.ParseDocumentContext(context.Background(), explained, pageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find the attempt that is used for the article.
+	events := before
+	if len(attempts) > 0 {
+		usedAttempt := len(attempts) - 1
+		if article.Extraction.UsedFallback {
+			// Same as grabArticle, the first longest attempt is used.
+			usedAttempt = 0
+			lengths := article.Extraction.AttemptTextLengths
+			for i := 1; i < len(lengths) && i < len(attempts); i++ {
+				if lengths[i] > lengths[usedAttempt] {
+					usedAttempt = i
+				}
+			}
+		}
+		events = append(events, attempts[usedAttempt]...)
+	}
+	events = append(events, after...)
+
+	for _, event := range events {
+		if event.Node == nil {
+			continue
+		}
+
+		element, exist := elements[dom.GetAttribute(event.Node, explainIDAttr)]
+		if !exist {
+			continue
+		}
+
+		switch event.Action {
+		case TraceRemove:
+			if !dom.HasAttribute(element, "data-readability-removed") {
+				dom.SetAttribute(element, "data-readability-removed", event.Reason)
+			}
+		case TraceScore:
+			dom.SetAttribute(element, "data-readability-score", strconv.FormatFloat(event.Score, 'f', -1, 64))
+		case TraceSelect:
+			dom.SetAttribute(element, "data-readability-selected", event.Reason)
+		case TraceSkip:
+			dom.SetAttribute(element, "data-readability-skipped", event.Reason)
+		case TraceConvert:
+			dom.SetAttribute(element, "data-readability-converted", event.Tag)
+		}
+	}
+
+	for _, element := range elements {
+		dom.RemoveAttribute(element, explainIDAttr)
+	}
+
+	// Annotate the remaining measurements. The class weight is shown even
+	// when the used attempt ignores it.
+	weightFlags := flags{useWeightClasses: true}
+	if bodies := dom.GetElementsByTagName(explained, "body"); len(bodies) > 0 {
+		bodyElements := append([]*html.Node{bodies[0]}, dom.GetElementsByTagName(bodies[0], "*")...)
+		for _, element := range bodyElements {
+			classWeight := ps.getClassWeightWithFlags(element, weightFlags)
+			linkDensity := ps.getLinkDensity(element)
+			dom.SetAttribute(element, "data-readability-class-weight", strconv.Itoa(classWeight))
+			dom.SetAttribute(element, "data-readability-link-density", strconv.FormatFloat(linkDensity, 'f', 2, 64))
+		}
+	}
+
+	ps.injectExplainStyle(explained)
+	return explained, nil
+}
+
+// newOptionsParser returns a new parser that only has the public options
+// of this parser, without the state of its previous parses.
+func (ps *Parser) newOptionsParser() Parser {
+	return Parser{
+		MaxElemsToParse:    ps.MaxElemsToParse,
+		NTopCandidates:     ps.NTopCandidates,
+		CharThresholds:     ps.CharThresholds,
+		ClassesToPreserve:  ps.ClassesToPreserve,
+		KeepClasses:        ps.KeepClasses,
+		TagsToScore:        ps.TagsToScore,
+		Debug:              ps.Debug,
+		Tracer:             ps.Tracer,
+		DisableJSONLD:      ps.DisableJSONLD,
+		AllowedVideoRegex:  ps.AllowedVideoRegex,
+		RequireContent:     ps.RequireContent,
+		CharsetHint:        ps.CharsetHint,
+		LanguageHint:       ps.LanguageHint,
+		ForceCharset:       ps.ForceCharset,
+		MaxPages:           ps.MaxPages,
+		PreferLargestImage: ps.PreferLargestImage,
+		PageFetcher:        ps.PageFetcher,
+		Classifiers:        ps.Classifiers,
+		SiteRules:          ps.SiteRules,
+		ExtractComments:    ps.ExtractComments,
+		ThreadMode:         ps.ThreadMode,
+		GenerateOutline:    ps.GenerateOutline,
+		WordsPerMinute:     ps.WordsPerMinute,
+	}
+}
+
+// injectExplainStyle adds the stylesheet of the explained document and
+// the content security policy that blocks its scripts.
+func (ps *Parser) injectExplainStyle(doc *html.Node) {
+	heads := dom.GetElementsByTagName(doc, "head")
+	if len(heads) == 0 {
+		return
+	}
+
+	csp := dom.CreateElement("meta")
+	dom.SetAttribute(csp, "http-equiv", "Content-Security-Policy")
+	dom.SetAttribute(csp, "content", "script-src 'none'")
+	dom.PrependChild(heads[0], csp)
+
+	style := dom.CreateElement("style")
+	dom.SetTextContent(style, explainStyle)
+	dom.AppendChild(heads[0], style)
+}
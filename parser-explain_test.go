@@ -0,0 +1,102 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-shiori/dom"
+)
+
+func Test_Explain(t *testing.T) {
+	content := loremParagraph(20)
+	source := `<html><head><title>Explained</title></head><body>` +
+		`<div id="sidebar"><p>Popular posts</p></div>` +
+		`<div id="hidden" style="display:none"><p>Hidden</p></div>` +
+		`<article id="article">` + content + content + `</article>` +
+		`</body></html>`
+
+	// The state of a previous parse must not leak into the explanation
+	parser := NewParser()
+	if _, err := parser.Parse(strings.NewReader("<html><body><p>Short text</p></body></html>"), fakeHostURL); err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	doc, err := parser.Explain(strings.NewReader(source), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to explain: %v", err)
+	}
+
+	scenarios := map[string]map[string]string{
+		"#sidebar": {"data-readability-removed": "unlikely candidate"},
+		"#hidden":  {"data-readability-removed": "hidden node"},
+		"#article": {
+			"data-readability-selected":     "top candidate",
+			"data-readability-class-weight": "25",
+			"data-readability-link-density": "0.00",
+		},
+	}
+
+	for selector, attributes := range scenarios {
+		node := dom.QuerySelector(doc, selector)
+		for name, expected := range attributes {
+			if value := dom.GetAttribute(node, name); value != expected {
+				t.Errorf("\n"+
+					"selector : %s\n"+
+					"attribute: %s\n"+
+					"want     : %q\n"+
+					"got      : %q", selector, name, expected, value)
+			}
+		}
+	}
+
+	if score := dom.GetAttribute(dom.QuerySelector(doc, "#article"), "data-readability-score"); score == "" {
+		t.Errorf("article is not scored")
+	}
+
+	if nodes := dom.QuerySelectorAll(doc, "["+explainIDAttr+"]"); len(nodes) > 0 {
+		t.Errorf("%d nodes still have the %s attribute", len(nodes), explainIDAttr)
+	}
+
+	if dom.QuerySelector(doc, `head > meta[http-equiv="Content-Security-Policy"]`) == nil ||
+		dom.QuerySelector(doc, "head > style") == nil {
+		t.Errorf("stylesheet or content security policy is not injected")
+	}
+}
+
+func Test_ExplainAttempts(t *testing.T) {
+	// The sidebar is only kept once stripUnlikelys is disabled, so the
+	// article is the second of four attempts.
+	source := `<html><body><p>Short text</p>` +
+		`<div id="sidebar"><p>` + strings.Repeat("Sidebar text. ", 10) + `</p></div>` +
+		`</body></html>`
+
+	var nStarted, nFinished int
+	parser := NewParser()
+	parser.Tracer = TracerFunc(func(event TraceEvent) {
+		switch {
+		case event.Action != TraceInfo:
+		case event.Reason == traceGrabbingArticle:
+			nStarted++
+		case event.Reason == traceAttemptFinished:
+			nFinished++
+		}
+	})
+
+	doc, err := parser.Explain(strings.NewReader(source), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to explain: %v", err)
+	}
+
+	if nStarted != 4 || nFinished != 4 {
+		t.Errorf("want 4 started and finished attempts, got %d and %d", nStarted, nFinished)
+	}
+
+	sidebar := dom.QuerySelector(doc, "#sidebar")
+	if removed := dom.GetAttribute(sidebar, "data-readability-removed"); removed != "" {
+		t.Errorf("want the decisions of the second attempt, but sidebar is removed as %q", removed)
+	}
+
+	if converted := dom.GetAttribute(sidebar, "data-readability-converted"); converted != "p" {
+		t.Errorf("want sidebar converted into p by the second attempt, got %q", converted)
+	}
+}
@@ -0,0 +1,55 @@
+package readability
+
+import (
+	"strings"
+
+	"github.com/go-shiori/dom"
+)
+
+// getArticleLanguage resolves the language of the article as BCP 47 tag,
+// along with the source it's found in. The sources are checked in order:
+// the lang attribute of html element, `LanguageHint` from the
+// Content-Language header, the content-language meta tag, og:locale and
+// the JSON-LD inLanguage. The first valid language is used, and if there
+// are none the language is detected from the text content.
+func (ps *Parser) getArticleLanguage(jsonLdLanguage string, textContent string) (string, LanguageSource) {
+	var htmlLang string
+	if root := dom.DocumentElement(ps.doc); root != nil {
+		htmlLang = dom.GetAttribute(root, "lang")
+	}
+
+	var httpEquivLang, ogLocale string
+	for _, meta := range dom.GetElementsByTagName(ps.doc, "meta") {
+		content := dom.GetAttribute(meta, "content")
+		switch {
+		case httpEquivLang == "" && strings.EqualFold(dom.GetAttribute(meta, "http-equiv"), "content-language"):
+			httpEquivLang = content
+		case ogLocale == "" && strings.EqualFold(dom.GetAttribute(meta, "property"), "og:locale"):
+			ogLocale = content
+		}
+	}
+
+	candidates := []struct {
+		lang   string
+		source LanguageSource
+	}{
+		{strOr(ps.articleLang, htmlLang), LanguageFromHTML},
+		{ps.LanguageHint, LanguageFromHeader},
+		{httpEquivLang, LanguageFromMetaHTTPEquiv},
+		{ogLocale, LanguageFromOpenGraph},
+		{jsonLdLanguage, LanguageFromJSONLD},
+	}
+
+	for _, candidate := range candidates {
+		if tag := normalizeLanguageTag(candidate.lang); tag != "" {
+			return tag, candidate.source
+		}
+	}
+
+	if tag := detectLanguage(textContent); tag != "" {
+		ps.traceInfo("language %q is detected from text", tag)
+		return tag, LanguageFromText
+	}
+
+	return "", ""
+}
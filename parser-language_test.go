@@ -0,0 +1,46 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_getArticleLanguage(t *testing.T) {
+	content := "<p>" + strings.Repeat("The quick brown fox jumps over the lazy dog, and then it runs away. ", 10) + "</p>"
+	makePage := func(htmlAttr, head string) string {
+		return `<html` + htmlAttr + `><head>` + head + `</head><body><article>` + content + `</article></body></html>`
+	}
+
+	scenarios := []struct {
+		name   string
+		hint   string
+		source string
+		tag    string
+		from   LanguageSource
+	}{
+		{"html", "fr", makePage(` lang="en_gb"`, `<meta property="og:locale" content="de_DE">`), "en-GB", LanguageFromHTML},
+		{"header", "fr-CA, en", makePage("", `<meta http-equiv="Content-Language" content="de">`), "fr-CA", LanguageFromHeader},
+		{"http-equiv", "", makePage("", `<meta http-equiv="Content-Language" content="de"><meta property="og:locale" content="es_ES">`), "de", LanguageFromMetaHTTPEquiv},
+		{"og:locale", "", makePage(` lang="invalid lang"`, `<meta property="og:locale" content="es_ES">`), "es-ES", LanguageFromOpenGraph},
+		{"json-ld", "", makePage("", `<script type="application/ld+json">{"@context":"https://schema.org","@type":"Article","inLanguage":"it"}</script>`), "it", LanguageFromJSONLD},
+		{"text", "", makePage("", ""), "en", LanguageFromText},
+	}
+
+	for _, scenario := range scenarios {
+		parser := NewParser()
+		parser.LanguageHint = scenario.hint
+		article, err := parser.Parse(strings.NewReader(scenario.source), fakeHostURL)
+		if err != nil {
+			t.Fatalf("%s: failed to parse: %v", scenario.name, err)
+		}
+
+		if article.LanguageTag != scenario.tag || article.LanguageSource != scenario.from {
+			t.Errorf("\n"+
+				"scenario : %s\n"+
+				"want     : %q from %q\n"+
+				"got      : %q from %q", scenario.name,
+				scenario.tag, scenario.from,
+				article.LanguageTag, article.LanguageSource)
+		}
+	}
+}
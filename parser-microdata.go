@@ -0,0 +1,208 @@
+package readability
+
+import (
+	"strings"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// getMicrodata extracts metadata from the first schema.org item of type
+// Article (or its subtypes) that is described using microdata (`itemscope`
+// and `itemprop`) or RDFa (`typeof` and `property`) in the document.
+func (ps *Parser) getMicrodata() map[string]string {
+	item := ps.findMicrodataArticle()
+	if item == nil {
+		return nil
+	}
+
+	props := microdataProperties(item)
+	metadata := make(map[string]string)
+
+	// Title
+	if headline := microdataText(props, "headline", "name"); headline != "" {
+		metadata["title"] = headline
+	}
+
+	// Author, there might be several of them
+	var authors []string
+	for _, node := range props["author"] {
+		name := microdataTextValue(node)
+		name = strings.Join(strings.Fields(name), " ")
+		if ps.isValidByline(name) && indexOf(authors, name) == -1 {
+			authors = append(authors, name)
+		}
+	}
+
+	if len(authors) > 0 {
+		metadata["byline"] = strings.Join(authors, ", ")
+	}
+
+	// Publisher
+	if publisher := microdataText(props, "publisher"); publisher != "" {
+		metadata["siteName"] = publisher
+	}
+
+	// DatePublished and DateModified
+	if datePublished := microdataText(props, "datePublished"); datePublished != "" {
+		metadata["datePublished"] = datePublished
+	}
+
+	if dateModified := microdataText(props, "dateModified"); dateModified != "" {
+		metadata["dateModified"] = dateModified
+	}
+
+	// Image
+	for _, node := range props["image"] {
+		if image := strings.TrimSpace(microdataValue(node, "url", "contentUrl")); image != "" {
+			metadata["image"] = image
+			break
+		}
+	}
+
+	return metadata
+}
+
+// findMicrodataArticle finds the first microdata or RDFa item whose type
+// is one of the schema.org article types.
+func (ps *Parser) findMicrodataArticle() *html.Node {
+	items := dom.QuerySelectorAll(ps.doc, "[itemscope][itemtype], [typeof]")
+	for _, item := range items {
+		itemType := dom.GetAttribute(item, "itemtype")
+		if !dom.HasAttribute(item, "itemscope") {
+			itemType = dom.GetAttribute(item, "typeof")
+		}
+
+		for _, strType := range strings.Fields(itemType) {
+			if isSchemaOrgArticleType(strType) {
+				return item
+			}
+		}
+	}
+
+	return nil
+}
+
+// isSchemaOrgArticleType checks if the microdata or RDFa type is one of the
+// schema.org article types. The type might be an absolute URL (microdata),
+// a prefixed name like "schema:Article", or a plain name when the RDFa
+// vocabulary is set by the `vocab` attribute.
+func isSchemaOrgArticleType(itemType string) bool {
+	name := microdataName(itemType)
+	return !strings.Contains(name, "://") && rxJsonLdArticleTypes.MatchString(name)
+}
+
+// microdataProperties collects the properties of the microdata or RDFa item.
+// Properties of the nested items are not included, but the nested items
+// themselves might be properties of the item (e.g. the author).
+func microdataProperties(item *html.Node) map[string][]*html.Node {
+	props := make(map[string][]*html.Node)
+
+	var walk func(*html.Node)
+	walk = func(node *html.Node) {
+		for _, child := range dom.Children(node) {
+			for _, name := range strings.Fields(microdataPropAttr(child)) {
+				name = microdataName(name)
+				props[name] = append(props[name], child)
+			}
+
+			if !isMicrodataItem(child) {
+				walk(child)
+			}
+		}
+	}
+
+	walk(item)
+	return props
+}
+
+// microdataText returns the text of the first property that has
+// non-empty value, with its whitespaces collapsed.
+func microdataText(props map[string][]*html.Node, names ...string) string {
+	for _, name := range names {
+		for _, node := range props[name] {
+			value := strings.Join(strings.Fields(microdataTextValue(node)), " ")
+			if value != "" {
+				return value
+			}
+		}
+	}
+
+	return ""
+}
+
+// microdataValue returns the value of a property node. If the node is
+// a nested item, its value is taken from the first of the specified
+// properties of the nested item that is not empty.
+func microdataValue(node *html.Node, nestedProps ...string) string {
+	if isMicrodataItem(node) {
+		props := microdataProperties(node)
+		for _, name := range nestedProps {
+			for _, propNode := range props[name] {
+				if value := microdataValue(propNode); strings.TrimSpace(value) != "" {
+					return value
+				}
+			}
+		}
+
+		// RDFa might keep the value of item in its own attributes
+		if value := dom.GetAttribute(node, "content"); value != "" {
+			return value
+		}
+
+		return dom.TextContent(node)
+	}
+
+	// RDFa allows content attribute in any element
+	if dom.HasAttribute(node, "content") {
+		return dom.GetAttribute(node, "content")
+	}
+
+	return itemPropNodeValue(node)
+}
+
+// microdataTextValue returns the value of a property that is supposed to
+// be a text, like name or date. Many sites put the author name as the
+// text of a link, so for links the text is used instead of the URL.
+func microdataTextValue(node *html.Node) string {
+	if isMicrodataItem(node) {
+		return microdataValue(node, "name")
+	}
+
+	switch dom.TagName(node) {
+	case "a", "area":
+		if !dom.HasAttribute(node, "content") {
+			return dom.TextContent(node)
+		}
+	}
+
+	return microdataValue(node)
+}
+
+// isMicrodataItem checks if the node is a microdata or RDFa item.
+func isMicrodataItem(node *html.Node) bool {
+	return dom.HasAttribute(node, "itemscope") || dom.HasAttribute(node, "typeof")
+}
+
+// microdataPropAttr returns the property names of the node, which are
+// kept in `itemprop` for microdata or in `property` for RDFa.
+func microdataPropAttr(node *html.Node) string {
+	if dom.HasAttribute(node, "itemprop") {
+		return dom.GetAttribute(node, "itemprop")
+	}
+	return dom.GetAttribute(node, "property")
+}
+
+// microdataName removes the schema.org URL or prefix from the property or
+// type name, e.g. "http://schema.org/headline" and "schema:headline" become
+// "headline". Names from other vocabularies are returned as it is.
+func microdataName(name string) string {
+	switch {
+	case strings.HasPrefix(name, "schema:"):
+		return strings.TrimPrefix(name, "schema:")
+	case strings.Contains(name, "://schema.org/"), strings.Contains(name, "://www.schema.org/"):
+		return name[strings.LastIndex(name, "/")+1:]
+	default:
+		return name
+	}
+}
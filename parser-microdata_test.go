@@ -0,0 +1,81 @@
+package readability
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-shiori/dom"
+)
+
+func Test_getMicrodata(t *testing.T) {
+	scenarios := map[string]map[string]string{
+		// Microdata with nested items
+		`<article itemscope itemtype="http://schema.org/NewsArticle">
+			<h1 itemprop="headline">The   headline</h1>
+			<span itemprop="author" itemscope itemtype="http://schema.org/Person">
+				<a itemprop="url" href="/john"><span itemprop="name">John Doe</span></a>
+			</span>
+			<a itemprop="author" href="/jane">Jane Doe</a>
+			<time itemprop="datePublished" datetime="2020-01-02T03:04:05Z">Jan 2</time>
+			<meta itemprop="dateModified" content="2020-01-03">
+			<div itemprop="image" itemscope itemtype="http://schema.org/ImageObject">
+				<meta itemprop="url" content="/image.jpg">
+			</div>
+			<div itemprop="publisher" itemscope itemtype="http://schema.org/Organization">
+				<meta itemprop="name" content="Daily News">
+			</div>
+			<div itemscope itemtype="http://schema.org/Comment">
+				<span itemprop="author">Commenter</span>
+			</div>
+		</article>`: {
+			"title":         "The headline",
+			"byline":        "John Doe, Jane Doe",
+			"datePublished": "2020-01-02T03:04:05Z",
+			"dateModified":  "2020-01-03",
+			"image":         "/image.jpg",
+			"siteName":      "Daily News",
+		},
+
+		// RDFa with vocab
+		`<div vocab="http://schema.org/" typeof="BlogPosting">
+			<h1 property="headline">RDFa title</h1>
+			<span property="author" typeof="Person"><span property="name">Ann</span></span>
+			<span property="datePublished" content="2021-05-06">May 6</span>
+			<img property="image" src="/rdfa.png">
+		</div>`: {
+			"title":         "RDFa title",
+			"byline":        "Ann",
+			"datePublished": "2021-05-06",
+			"image":         "/rdfa.png",
+		},
+
+		// RDFa with prefix
+		`<div prefix="schema: http://schema.org/" typeof="schema:Article">
+			<h1 property="schema:headline">Prefixed title</h1>
+		</div>`: {
+			"title": "Prefixed title",
+		},
+
+		// Item which is not an article
+		`<div itemscope itemtype="http://schema.org/Product"><h1 itemprop="name">Product</h1></div>`: nil,
+	}
+
+	for source, expected := range scenarios {
+		doc, err := dom.Parse(strings.NewReader(source))
+		if err != nil {
+			t.Fatalf("failed to parse %q: %v", source, err)
+		}
+
+		parser := NewParser()
+		parser.doc = doc
+
+		metadata := parser.getMicrodata()
+		if result, want := fmt.Sprint(metadata), fmt.Sprint(expected); result != want {
+			t.Errorf("\n"+
+				"source : %s\n"+
+				"want   : %s\n"+
+				"got    : %s", source, want, result)
+		}
+	}
+}
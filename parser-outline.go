@@ -0,0 +1,107 @@
+package readability
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// Heading is a heading in the article outline.
+type Heading struct {
+	// Level is the level of the heading in the article content, from 1
+	// for `h1` to 6 for `h6`. Since the article title is the only `h1`,
+	// the headings in the content usually start from level 2.
+	Level int
+	Text  string
+	// ID is the anchor id of the heading in the article content. It's the
+	// original id of the heading if it has one, or else a slug of its text.
+	ID       string
+	Children []Heading
+}
+
+// getArticleOutline finds the headings in the article content and returns
+// them as a nested outline, where each heading contains the headings with
+// a lower level that follow it. Every heading without id gets an unique id
+// that is generated from its text, so it can be linked from the outline.
+func (ps *Parser) getArticleOutline(articleContent *html.Node) []Heading {
+	usedIDs := map[string]struct{}{}
+	for _, node := range dom.QuerySelectorAll(articleContent, "[id]") {
+		usedIDs[dom.ID(node)] = struct{}{}
+	}
+
+	var outline []Heading
+	var levels []int
+	headings := dom.QuerySelectorAll(articleContent, "h1, h2, h3, h4, h5, h6")
+	for _, node := range headings {
+		text := ps.getInnerText(node, true)
+		if text == "" {
+			continue
+		}
+
+		id := dom.ID(node)
+		if id == "" {
+			id = uniqueSlug(text, usedIDs)
+			dom.SetAttribute(node, "id", id)
+		}
+
+		level, _ := strconv.Atoi(dom.TagName(node)[1:])
+		heading := Heading{Level: level, Text: text, ID: id}
+
+		// Close the sections that are not higher than this heading, then
+		// append it to the deepest section that is still open.
+		for len(levels) > 0 && levels[len(levels)-1] >= level {
+			levels = levels[:len(levels)-1]
+		}
+
+		siblings := &outline
+		for range levels {
+			last := &(*siblings)[len(*siblings)-1]
+			siblings = &last.Children
+		}
+
+		*siblings = append(*siblings, heading)
+		levels = append(levels, level)
+	}
+
+	return outline
+}
+
+// uniqueSlug converts the text into a slug that can be used as id, e.g.
+// "Hello, World!" becomes "hello-world". If the slug is already used, a
+// number is appended to make it unique. The returned slug is added to the
+// used ids.
+func uniqueSlug(text string, usedIDs map[string]struct{}) string {
+	var sb strings.Builder
+	needDash := false
+	for _, r := range strings.ToLower(text) {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			needDash = sb.Len() > 0
+			continue
+		}
+
+		if needDash {
+			sb.WriteByte('-')
+			needDash = false
+		}
+		sb.WriteRune(r)
+	}
+
+	slug := sb.String()
+	if slug == "" {
+		slug = "section"
+	}
+
+	id := slug
+	for i := 1; ; i++ {
+		if _, used := usedIDs[id]; !used {
+			break
+		}
+		id = slug + "-" + strconv.Itoa(i)
+	}
+
+	usedIDs[id] = struct{}{}
+	return id
+}
@@ -0,0 +1,94 @@
+package readability
+
+import (
+	"encoding/json"
+	"os"
+	fp "path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-shiori/dom"
+)
+
+func Test_articleOutline(t *testing.T) {
+	for _, itemName := range []string{"toc-missing", "wikipedia"} {
+		t.Run(itemName, func(t1 *testing.T) {
+			sourcePath := fp.Join("test-pages", itemName, "source.html")
+			expectedPath := fp.Join("test-pages", itemName, "expected-outline.json")
+
+			expectedFile, err := os.ReadFile(expectedPath)
+			if err != nil {
+				t1.Fatalf("failed to open expected outline: %v", err)
+			}
+
+			var expected []Heading
+			if err = json.Unmarshal(expectedFile, &expected); err != nil {
+				t1.Fatalf("failed to decode expected outline: %v", err)
+			}
+
+			f, err := os.Open(sourcePath)
+			if err != nil {
+				t1.Fatalf("failed to open source: %v", err)
+			}
+			defer f.Close()
+
+			parser := NewParser()
+			parser.GenerateOutline = true
+			article, err := parser.Parse(f, fakeHostURL)
+			if err != nil {
+				t1.Fatalf("failed to parse: %v", err)
+			}
+
+			if !reflect.DeepEqual(article.Outline, expected) {
+				got, _ := json.MarshalIndent(article.Outline, "", "  ")
+				t1.Fatalf("unexpected outline:\n%s", got)
+			}
+
+			// Every heading in outline must be linkable
+			var checkIDs func([]Heading)
+			checkIDs = func(headings []Heading) {
+				for _, heading := range headings {
+					if !strings.Contains(article.Content, `id="`+heading.ID+`"`) {
+						t1.Errorf("anchor id %q is not found in content", heading.ID)
+					}
+					checkIDs(heading.Children)
+				}
+			}
+			checkIDs(article.Outline)
+		})
+	}
+}
+
+func Test_getArticleOutline(t *testing.T) {
+	source := `<html><body><article>` +
+		`<h2>Intro</h2><p>Lorem ipsum dolor sit amet.</p>` +
+		`<h4>Deep, "nested" heading!</h4><p>Lorem ipsum dolor sit amet.</p>` +
+		`<h3>Intro</h3><p>Lorem ipsum dolor sit amet.</p>` +
+		`<h2 id="custom">Custom id</h2><p id="intro-2">Lorem ipsum dolor sit amet.</p>` +
+		`<h2>Intro</h2><h2>???</h2>` +
+		`</article></body></html>`
+
+	doc, err := dom.Parse(strings.NewReader(source))
+	if err != nil {
+		t.Fatalf("failed to parse source: %v", err)
+	}
+
+	parser := NewParser()
+	outline := parser.getArticleOutline(doc)
+	expected := []Heading{
+		{Level: 2, Text: "Intro", ID: "intro", Children: []Heading{
+			{Level: 4, Text: `Deep, "nested" heading!`, ID: "deep-nested-heading"},
+			{Level: 3, Text: "Intro", ID: "intro-1"},
+		}},
+		{Level: 2, Text: "Custom id", ID: "custom"},
+		{Level: 2, Text: "Intro", ID: "intro-3"},
+		{Level: 2, Text: "???", ID: "section"},
+	}
+
+	if !reflect.DeepEqual(outline, expected) {
+		t.Errorf("\n"+
+			"want : %+v\n"+
+			"got  : %+v", expected, outline)
+	}
+}
@@ -0,0 +1,391 @@
+package readability
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	nurl "net/url"
+	"strconv"
+	"strings"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// nextPageCandidate is a link that might point to the next page of
+// the article, along with its score.
+type nextPageCandidate struct {
+	href     string
+	linkText string
+	score    int
+}
+
+// appendNextPages follows the links to the next pages of the article,
+// then appends the content of each page into articleContent as its own
+// `readability-page-N` div. It returns the number of pages in the article.
+func (ps *Parser) appendNextPages(ctx context.Context, articleContent *html.Node) (int, error) {
+	nPages := 1
+	if ps.MaxPages <= 1 || ps.documentURI == nil {
+		return nPages, nil
+	}
+
+	parsedPages := map[string]struct{}{
+		ps.cleanPageURL(ps.documentURI.String()): {},
+	}
+
+	nextPageURL := ps.findNextPageLink(ps.doc, ps.documentURI, parsedPages)
+	for nextPageURL != nil && nPages < ps.MaxPages {
+		parsedPages[ps.cleanPageURL(nextPageURL.String())] = struct{}{}
+
+		page, followingPageURL, err := ps.grabNextPage(ctx, nextPageURL, parsedPages)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nPages, ctxErr
+			}
+
+			ps.traceInfo("failed to grab next page %s: %v", nextPageURL, err)
+			break
+		}
+
+		if page == nil {
+			ps.traceInfo("no content found in next page %s", nextPageURL)
+			break
+		}
+
+		if ps.isDuplicatePage(articleContent, page) {
+			ps.traceInfo("next page %s is duplicate of previous page, skipping", nextPageURL)
+			break
+		}
+
+		nPages++
+		dom.SetAttribute(page, "id", fmt.Sprintf("readability-page-%d", nPages))
+		dom.SetAttribute(page, "class", "page")
+		dom.AppendChild(articleContent, page)
+		nextPageURL = followingPageURL
+	}
+
+	return nPages, nil
+}
+
+// grabNextPage fetches the page in pageURL and grabs its article content.
+// It also returns link to the page after it, if there are any. The state
+// of the parser is restored once it's done, so the metadata of the first
+// page is kept intact.
+func (ps *Parser) grabNextPage(ctx context.Context, pageURL *nurl.URL, parsedPages map[string]struct{}) (*html.Node, *nurl.URL, error) {
+	fetcher := ps.PageFetcher
+	if fetcher == nil {
+		fetcher = HTTPFetcher{}
+	}
+
+	resp, err := fetcher.Fetch(ctx, pageURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	content, err := readResponse(resp, pageURL, ps.maxBodySize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	charsetHint := strOr(contentTypeCharset(resp.Header.Get("Content-Type")), ps.CharsetHint)
+	doc, _, err := ps.parseHTML(bytes.NewReader(content), charsetHint)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse page: %w", err)
+	}
+
+	// Save the state of the first page, then restore it after we are done.
+	firstDoc, firstURI, firstBaseURI, firstLang := ps.doc, ps.documentURI, ps.baseURI, ps.articleLang
+	firstAttempts, firstFlags := ps.attempts, ps.flags
+	firstConfidence, firstExtraction := ps.articleConfidence, ps.extraction
+	firstDir, firstByline := ps.articleDir, ps.articleByline
+	defer func() {
+		ps.doc, ps.documentURI, ps.baseURI, ps.articleLang = firstDoc, firstURI, firstBaseURI, firstLang
+		ps.attempts, ps.flags = firstAttempts, firstFlags
+		ps.articleConfidence, ps.extraction = firstConfidence, firstExtraction
+		ps.articleDir, ps.articleByline = firstDir, firstByline
+	}()
+
+	ps.doc = doc
+	ps.documentURI = pageURL
+	ps.baseURI = ps.findBaseURI()
+	ps.attempts = []parseAttempt{}
+	ps.flags = flags{
+		stripUnlikelys:     true,
+		useWeightClasses:   true,
+		cleanConditionally: true,
+	}
+
+	ps.unwrapNoscriptImages(ps.doc)
+	ps.removeScripts(ps.doc)
+	ps.prepDocument()
+
+	followingPageURL := ps.findNextPageLink(ps.doc, pageURL, parsedPages)
+	articleContent, err := ps.grabArticle(ctx)
+	if err != nil || articleContent == nil {
+		return nil, nil, err
+	}
+
+	// Since the URIs are relative to this page instead of the first
+	// page, they must be fixed now before it's merged.
+	ps.fixRelativeURIs(articleContent)
+	return dom.FirstElementChild(articleContent), followingPageURL, nil
+}
+
+// isDuplicatePage checks if the first paragraph of page already
+// exists in one of the pages that already grabbed.
+func (ps *Parser) isDuplicatePage(articleContent *html.Node, page *html.Node) bool {
+	paragraphs := dom.GetElementsByTagName(page, "p")
+	firstParagraph := page
+	if len(paragraphs) > 0 {
+		firstParagraph = paragraphs[0]
+	}
+
+	text := ps.getInnerText(firstParagraph, true)
+	if text == "" {
+		return false
+	}
+
+	return ps.someNode(dom.Children(articleContent), func(existingPage *html.Node) bool {
+		return strings.Contains(ps.getInnerText(existingPage, true), text)
+	})
+}
+
+// findNextPageLink looks for the link to the next page of the article in
+// the document. Links to pages that already parsed are ignored. Returns
+// nil if there are no link that likely points to the next page.
+func (ps *Parser) findNextPageLink(doc *html.Node, pageURL *nurl.URL, parsedPages map[string]struct{}) *nurl.URL {
+	articleBaseURL := ps.findBaseURL(ps.documentURI)
+	possiblePages := make(map[string]*nextPageCandidate)
+	var candidateOrder []string
+
+	addCandidate := func(href string, linkText string) *nextPageCandidate {
+		if candidate, exist := possiblePages[href]; exist {
+			candidate.linkText += " | " + linkText
+			return candidate
+		}
+
+		candidate := &nextPageCandidate{href: href, linkText: linkText}
+		possiblePages[href] = candidate
+		candidateOrder = append(candidateOrder, href)
+		return candidate
+	}
+
+	isValidHref := func(href string) bool {
+		if href == "" || href == articleBaseURL || href == ps.cleanPageURL(pageURL.String()) {
+			return false
+		}
+
+		// If we've already seen this page, ignore it
+		if _, parsed := parsedPages[href]; parsed {
+			return false
+		}
+
+		// If it's on a different domain, skip it.
+		tmp, err := nurl.Parse(href)
+		return err == nil && tmp.Host == ps.documentURI.Host
+	}
+
+	// Links marked with rel=next are explicitly pointing to the next
+	// page, so they are given a score that is good enough on its own.
+	for _, link := range dom.QuerySelectorAll(doc, `link[rel~="next"], a[rel~="next"]`) {
+		href := ps.cleanPageURL(toAbsoluteURI(dom.GetAttribute(link, "href"), ps.baseURI))
+		if isValidHref(href) {
+			addCandidate(href, ps.getInnerText(link, true)).score += 100
+		}
+	}
+
+	body := doc
+	if bodies := dom.GetElementsByTagName(doc, "body"); len(bodies) > 0 {
+		body = bodies[0]
+	}
+
+	for _, link := range dom.GetElementsByTagName(body, "a") {
+		linkHref := ps.cleanPageURL(toAbsoluteURI(dom.GetAttribute(link, "href"), ps.baseURI))
+		if !isValidHref(linkHref) {
+			continue
+		}
+
+		// If the linkText looks like it's not the next page, skip it.
+		linkText := ps.getInnerText(link, true)
+		if rxExtraneous.MatchString(linkText) || charCount(linkText) > 25 {
+			continue
+		}
+
+		// If the leftovers of the URL after removing the base URL don't
+		// contain any digits, it's certainly not a next page link.
+		linkHrefLeftover := strings.Replace(linkHref, articleBaseURL, "", 1)
+		if !strings.ContainsAny(linkHrefLeftover, "0123456789") {
+			continue
+		}
+
+		candidate := addCandidate(linkHref, linkText)
+
+		// If the articleBaseUrl isn't part of this URL, penalize this link.
+		if !strings.HasPrefix(linkHref, articleBaseURL) {
+			candidate.score -= 25
+		}
+
+		linkData := linkText + " " + dom.ClassName(link) + " " + dom.ID(link)
+		if rxNextLink.MatchString(linkData) {
+			candidate.score += 50
+		}
+
+		if rxPaging.MatchString(linkData) {
+			candidate.score += 25
+		}
+
+		if rxFirstOrLast.MatchString(linkData) {
+			// -65 is enough to negate any bonuses gotten from a > or » in
+			// the text, if a link has "first" or "last" in it.
+			if !rxNextLink.MatchString(candidate.linkText) {
+				candidate.score -= 65
+			}
+		}
+
+		if ps.isNegativeClass(linkData) || rxExtraneous.MatchString(linkData) {
+			candidate.score -= 50
+		}
+
+		if rxPrevLink.MatchString(linkData) {
+			candidate.score -= 200
+		}
+
+		// If a parentNode contains page or paging or paginat
+		positiveNodeMatch := false
+		negativeNodeMatch := false
+		for parent := link.Parent; parent != nil; parent = parent.Parent {
+			parentClassAndID := dom.ClassName(parent) + " " + dom.ID(parent)
+			if parentClassAndID == " " {
+				continue
+			}
+
+			if !positiveNodeMatch && rxPaging.MatchString(parentClassAndID) {
+				positiveNodeMatch = true
+				candidate.score += 25
+			}
+
+			// If this is just something like "footer", give it a negative.
+			// If it's something like "body-and-footer", leave it be.
+			if !negativeNodeMatch && ps.isNegativeClass(parentClassAndID) &&
+				!ps.isPositiveClass(parentClassAndID) {
+				negativeNodeMatch = true
+				candidate.score -= 25
+			}
+		}
+
+		// If the URL looks like it has paging in it, add to the score.
+		if rxPagingURL.MatchString(linkHref) {
+			candidate.score += 25
+		}
+
+		// If the URL contains negative values, give a slight decrease.
+		if rxExtraneous.MatchString(linkHref) {
+			candidate.score -= 15
+		}
+
+		// If the link text can be parsed as a number, give it a minor
+		// bonus, with a slight bias towards lower numbered pages. This is
+		// so that pages that might not have 'next' in their text can still
+		// get scored, and sorted properly by score.
+		if parts := rxLeadingNumber.FindStringSubmatch(linkText); len(parts) > 1 {
+			if linkTextAsNumber, _ := strconv.Atoi(parts[1]); linkTextAsNumber == 1 {
+				// Punish 1 since we're either already there, or it's
+				// probably before what we want anyways.
+				candidate.score -= 10
+			} else if linkTextAsNumber > 1 && linkTextAsNumber < 10 {
+				candidate.score += 10 - linkTextAsNumber
+			}
+		}
+	}
+
+	// Loop through all of our possible pages and pick the top candidate
+	var topPage *nextPageCandidate
+	for _, href := range candidateOrder {
+		candidate := possiblePages[href]
+		if candidate.score >= 50 && (topPage == nil || topPage.score < candidate.score) {
+			topPage = candidate
+		}
+	}
+
+	if topPage == nil {
+		return nil
+	}
+
+	ps.traceInfo("found next page %s with score %d", topPage.href, topPage.score)
+	nextPageURL, err := nurl.Parse(topPage.href)
+	if err != nil {
+		return nil
+	}
+
+	return nextPageURL
+}
+
+// findBaseURL returns the URL of the article with the page number
+// removed, e.g. "http://site/article/2" becomes "http://site/article".
+// It's used to check whether a link points to the same article.
+func (ps *Parser) findBaseURL(pageURL *nurl.URL) string {
+	if pageURL == nil {
+		return ""
+	}
+
+	urlSlashes := strings.Split(pageURL.Path, "/")
+	var cleanedSegments []string
+	for i := len(urlSlashes) - 1; i >= 0; i-- {
+		segment := urlSlashes[i]
+		position := len(urlSlashes) - 1 - i
+
+		// Split off and save anything that looks like a file type.
+		if dotIndex := strings.Index(segment, "."); dotIndex != -1 {
+			possibleType := strings.SplitN(segment[dotIndex+1:], ".", 2)[0]
+
+			// If the type isn't alpha-only, it's probably not actually a file extension.
+			if strings.Trim(strings.ToLower(possibleType), "abcdefghijklmnopqrstuvwxyz") == "" {
+				segment = segment[:dotIndex]
+			}
+		}
+
+		// EW-CMS specific segment replacement. Ugly.
+		segment = strings.Replace(segment, ",00", "", 1)
+
+		// If our first or second segment has anything looking like a page
+		// number, remove it.
+		if position < 2 && rxURLPageNumber.MatchString(segment) {
+			segment = rxURLPageNumber.ReplaceAllString(segment, "")
+		}
+
+		// If this is purely a number, and it's the first or second
+		// segment, it's probably a page number. Remove it.
+		del := position < 2 && len(segment) > 0 && len(segment) <= 2 &&
+			strings.Trim(segment, "0123456789") == ""
+
+		// If this is the first segment and it's just "index", remove it.
+		if position == 0 && strings.ToLower(segment) == "index" {
+			del = true
+		}
+
+		// If our first or second segment is smaller than 3 characters,
+		// and the first segment was purely alphas, remove it.
+		// Note that, just like Readability.js, it's actually checking
+		// whether the first segment has no alphabet at all.
+		firstSegment := strings.ToLower(urlSlashes[len(urlSlashes)-1])
+		if position < 2 && len(segment) < 3 && !strings.ContainsAny(firstSegment, "abcdefghijklmnopqrstuvwxyz") {
+			del = true
+		}
+
+		if !del {
+			cleanedSegments = append([]string{segment}, cleanedSegments...)
+		}
+	}
+
+	return pageURL.Scheme + "://" + pageURL.Host + strings.TrimSuffix(strings.Join(cleanedSegments, "/"), "/")
+}
+
+// cleanPageURL removes the fragment and trailing slash from href,
+// so the same page is always represented by the same URL.
+func (ps *Parser) cleanPageURL(href string) string {
+	if idx := strings.Index(href, "#"); idx >= 0 {
+		href = href[:idx]
+	}
+	return strings.TrimSuffix(href, "/")
+}
@@ -0,0 +1,120 @@
+package readability
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	nurl "net/url"
+	"strings"
+	"testing"
+
+	"github.com/go-shiori/dom"
+)
+
+func Test_findBaseURL(t *testing.T) {
+	scenarios := map[string]string{
+		"http://fakehost/news/story":             "http://fakehost/news/story",
+		"http://fakehost/news/story/2":           "http://fakehost/news/story",
+		"http://fakehost/news/story/p3":          "http://fakehost/news/story",
+		"http://fakehost/news/story_p2.html":     "http://fakehost/news/story",
+		"http://fakehost/news/story/index.html":  "http://fakehost/news/story",
+		"http://fakehost/news/story.html?page=2": "http://fakehost/news/story",
+		"http://fakehost/news/long-story-2.html": "http://fakehost/news/long-story",
+	}
+
+	ps := NewParser()
+	for rawURL, expected := range scenarios {
+		pageURL, _ := nurl.Parse(rawURL)
+		if result := ps.findBaseURL(pageURL); result != expected {
+			t.Errorf("\n"+
+				"url  : %s\n"+
+				"want : %s\n"+
+				"got  : %s", rawURL, expected, result)
+		}
+	}
+}
+
+func Test_multiPageArticle(t *testing.T) {
+	makePage := func(number int, nextLink string) string {
+		var sb strings.Builder
+		sb.WriteString("<html><head><title>A very long story split into pages</title></head><body>")
+		sb.WriteString(`<div class="article">`)
+		for i := 1; i <= 5; i++ {
+			fmt.Fprintf(&sb, "<p>This is paragraph %d of page %d. It has enough text, commas, "+
+				"and words to be considered as a content by readability, so it will be kept.</p>", i, number)
+		}
+		sb.WriteString(`</div><div class="pagination">` + nextLink + `</div></body></html>`)
+		return sb.String()
+	}
+
+	pages := map[string]string{
+		"/story":   makePage(1, `<a href="/story/2">Next page</a>`),
+		"/story/2": makePage(2, `<a href="/story">1</a> <a href="/story/3">Next page</a>`),
+		"/story/3": makePage(3, `<a href="/story/2">Previous page</a>`),
+	}
+
+	// The second page has its own direction and byline, which must not
+	// leak into the article.
+	pages["/story/2"] = strings.Replace(pages["/story/2"], `<div class="article">`,
+		`<div class="article" dir="rtl"><p class="byline">By Mallory Page</p>`, 1)
+
+	var fetched []string
+	fetcher := FetcherFunc(func(ctx context.Context, pageURL *nurl.URL) (*http.Response, error) {
+		fetched = append(fetched, pageURL.Path)
+		page, exist := pages[pageURL.Path]
+		if !exist {
+			return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader(""))}, nil
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(page))}, nil
+	})
+
+	pageURL, _ := nurl.Parse("http://fakehost/story")
+	parser := NewParser()
+	parser.MaxPages = 5
+	parser.PageFetcher = fetcher
+
+	article, err := parser.Parse(strings.NewReader(pages["/story"]), pageURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if expected := []string{"/story/2", "/story/3"}; strings.Join(fetched, " ") != strings.Join(expected, " ") {
+		t.Errorf("fetched pages, want %v got %v", expected, fetched)
+	}
+
+	if article.Dir != "" || article.Byline != "" {
+		t.Errorf("want direction and byline of the first page, got %q and %q", article.Dir, article.Byline)
+	}
+
+	for i := 1; i <= 3; i++ {
+		page := dom.GetElementByID(article.Node, fmt.Sprintf("readability-page-%d", i))
+		if page == nil {
+			t.Errorf("page %d is missing", i)
+			continue
+		}
+
+		if text := dom.TextContent(page); !strings.Contains(text, fmt.Sprintf("paragraph 1 of page %d", i)) {
+			t.Errorf("page %d has unexpected content: %q", i, text)
+		}
+	}
+
+	// Next pages are limited by the max body size as well
+	pages["/story/3"] = strings.Replace(pages["/story/3"], "<body>", "<body><!-- "+strings.Repeat("x", 5000)+" -->", 1)
+	article, err = FromURLWithOptions(context.Background(), pageURL.String(),
+		WithFetcher(fetcher), WithParser(&parser), WithMaxBodySize(int64(len(pages["/story"])*2)))
+	if err != nil {
+		t.Fatalf("failed to parse with max body size: %v", err)
+	}
+
+	if dom.GetElementByID(article.Node, "readability-page-2") == nil || dom.GetElementByID(article.Node, "readability-page-3") != nil {
+		t.Errorf("want only 2 pages when the third page is too large, got %q", article.TextContent)
+	}
+
+	// Without MaxPages, next pages should not be fetched
+	fetched = nil
+	parser.MaxPages = 0
+	if _, err = parser.Parse(strings.NewReader(pages["/story"]), pageURL); err != nil || len(fetched) > 0 {
+		t.Errorf("disabled multi-page, want no fetch got %v (%v)", fetched, err)
+	}
+}
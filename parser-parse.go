@@ -1,9 +1,11 @@
 package readability
 
 import (
+	"context"
 	"fmt"
 	"io"
 	nurl "net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -14,27 +16,57 @@ import (
 
 // Parse parses a reader and find the main readable content.
 func (ps *Parser) Parse(input io.Reader, pageURL *nurl.URL) (Article, error) {
+	return ps.ParseContext(context.Background(), input, pageURL)
+}
+
+// ParseContext is like `Parse`, but it stops parsing and returns
+// `ctx.Err()` once the context is cancelled or its deadline is exceeded.
+func (ps *Parser) ParseContext(ctx context.Context, input io.Reader, pageURL *nurl.URL) (Article, error) {
 	// Parse input
-	doc, err := dom.Parse(input)
+	doc, encoding, err := ps.parseHTML(input, ps.CharsetHint)
+	if err != nil {
+		return Article{}, fmt.Errorf("failed to parse input: %w", err)
+	}
+
+	article, err := ps.ParseDocumentContext(ctx, doc, pageURL)
 	if err != nil {
-		return Article{}, fmt.Errorf("failed to parse input: %v", err)
+		return Article{}, err
 	}
 
-	return ps.ParseDocument(doc, pageURL)
+	article.Encoding = encoding
+	return article, nil
 }
 
 // ParseDocument parses the specified document and find the main readable content.
 func (ps *Parser) ParseDocument(doc *html.Node, pageURL *nurl.URL) (Article, error) {
+	return ps.ParseDocumentContext(context.Background(), doc, pageURL)
+}
+
+// ParseDocumentContext is like `ParseDocument`, but it stops parsing and
+// returns `ctx.Err()` once the context is cancelled or its deadline is
+// exceeded. The context is checked between each phase of the parsing, so
+// a single phase won't be interrupted halfway.
+func (ps *Parser) ParseDocumentContext(ctx context.Context, doc *html.Node, pageURL *nurl.URL) (Article, error) {
+	if err := ctx.Err(); err != nil {
+		return Article{}, err
+	}
+
 	// Clone document to make sure the original kept untouched
 	ps.doc = dom.Clone(doc, true)
 
 	// Reset parser data
 	ps.articleTitle = ""
 	ps.articleByline = ""
+	ps.articleConfidence = 0
+	ps.extraction = ExtractionInfo{}
+	ps.jsonLdAuthors = nil
 	ps.articleDir = ""
+	ps.articleLang = ""
 	ps.articleSiteName = ""
 	ps.documentURI = pageURL
+	ps.baseURI = ps.findBaseURI()
 	ps.attempts = []parseAttempt{}
+	ps.hasKeptNodes = false
 	ps.flags = flags{
 		stripUnlikelys:     true,
 		useWeightClasses:   true,
@@ -45,7 +77,7 @@ func (ps *Parser) ParseDocument(doc *html.Node, pageURL *nurl.URL) (Article, err
 	if ps.MaxElemsToParse > 0 {
 		numTags := len(dom.GetElementsByTagName(ps.doc, "*"))
 		if numTags > ps.MaxElemsToParse {
-			return Article{}, fmt.Errorf("documents too large: %d elements", numTags)
+			return Article{}, &TooManyElementsError{Count: numTags, Max: ps.MaxElemsToParse}
 		}
 	}
 
@@ -58,24 +90,82 @@ func (ps *Parser) ParseDocument(doc *html.Node, pageURL *nurl.URL) (Article, err
 		jsonLd, _ = ps.getJSONLD()
 	}
 
+	// Comments in JSON-LD are also extracted before removing scripts
+	var comments []Comment
+	if ps.ExtractComments && !ps.DisableJSONLD {
+		comments = ps.getJSONLDComments()
+	}
+
 	// Remove script tags from the document.
 	ps.removeScripts(ps.doc)
 
 	// Prepares the HTML document
 	ps.prepDocument()
+	if err := ctx.Err(); err != nil {
+		return Article{}, err
+	}
+
+	// The language is read from the document, whether or not the
+	// article content can be found.
+	if root := dom.DocumentElement(ps.doc); root != nil {
+		ps.articleLang = dom.GetAttribute(root, "lang")
+	}
 
 	// Fetch metadata
-	metadata := ps.getArticleMetadata(jsonLd)
+	microdata := ps.getMicrodata()
+	metadata := ps.getArticleMetadata(jsonLd, microdata)
+	siteRule := findSiteRule(ps.SiteRules, pageURL)
+	ps.applySiteRuleMetadata(siteRule, metadata)
 	ps.articleTitle = metadata["title"]
 
-	// Try to grab article content
+	// Find authors before the byline is removed by grabArticle
+	documentAuthors := ps.getDocumentAuthors()
+
+	// Find comments before the comment section is removed by grabArticle
+	if ps.ExtractComments && len(comments) == 0 {
+		comments = ps.getDocumentComments()
+	}
+
+	// Try to grab article content, using the site rule if possible
 	finalHTMLContent := ""
 	finalTextContent := ""
-	articleContent := ps.grabArticle()
-	var readableNode *html.Node
+	ps.applySiteRuleSelectors(siteRule)
+	articleContent := ps.grabSiteRuleContent(siteRule)
+
+	var posts []Post
+	if articleContent == nil && ps.ThreadMode {
+		articleContent, posts = ps.grabThread()
+	}
 
+	if articleContent == nil {
+		var err error
+		articleContent, err = ps.grabArticle(ctx)
+		if err != nil {
+			return Article{}, err
+		}
+	}
+
+	if articleContent == nil && ps.RequireContent {
+		return Article{}, ErrNoContent
+	}
+
+	var outline []Heading
+	var readableNode *html.Node
 	if articleContent != nil {
+		nPages, err := ps.appendNextPages(ctx, articleContent)
+		if err != nil {
+			return Article{}, err
+		}
+
 		ps.postProcessContent(articleContent)
+		if err := ctx.Err(); err != nil {
+			return Article{}, err
+		}
+
+		// Generate the outline, which adds the anchor ids to the headings
+		if ps.GenerateOutline {
+			outline = ps.getArticleOutline(articleContent)
+		}
 
 		// If we haven't found an excerpt in the article's metadata,
 		// use the article's first paragraph as the excerpt. This is used
@@ -88,6 +178,9 @@ func (ps *Parser) ParseDocument(doc *html.Node, pageURL *nurl.URL) (Article, err
 		}
 
 		readableNode = dom.FirstElementChild(articleContent)
+		if nPages > 1 {
+			readableNode = articleContent
+		}
 		finalHTMLContent = dom.InnerHTML(articleContent)
 		finalTextContent = dom.TextContent(articleContent)
 		finalTextContent = strings.TrimSpace(finalTextContent)
@@ -106,35 +199,86 @@ func (ps *Parser) ParseDocument(doc *html.Node, pageURL *nurl.URL) (Article, err
 	// go-readability special:
 	// Internet is dangerous and weird, and sometimes we will find
 	// metadata isn't encoded using a valid Utf-8, so here we check it.
-	var replacementTitle string
+	var strPageURL string
 	if pageURL != nil {
-		replacementTitle = pageURL.String()
+		strPageURL = pageURL.String()
 	}
 
-	validTitle := strings.ToValidUTF8(ps.articleTitle, replacementTitle)
+	validTitle := strings.ToValidUTF8(ps.articleTitle, strPageURL)
 	validByline := strings.ToValidUTF8(finalByline, "")
 	validExcerpt := strings.ToValidUTF8(excerpt, "")
+	authors := ps.getArticleAuthors(documentAuthors, metadata["byline"])
 
 	publishedTime := ps.getDate(metadata, "publishedTime")
 	modifiedTime := ps.getDate(metadata, "modifiedTime")
 
+	// The html lang attribute is more reliable than the metadata,
+	// so the JSON-LD language is only used as fallback.
+	language := strOr(ps.articleLang, metadata["language"])
+	languageTag, languageSource := ps.getArticleLanguage(metadata["language"], finalTextContent)
+
+	var keywords []string
+	for _, keyword := range strings.Split(metadata["keywords"], ",") {
+		if keyword = strings.TrimSpace(keyword); keyword != "" {
+			keywords = append(keywords, keyword)
+		}
+	}
+
+	declaredWordCount, _ := strconv.Atoi(metadata["wordCount"])
+	nWords := wordCount(finalTextContent)
+
+	var isAccessibleForFree *bool
+	if free, err := strconv.ParseBool(metadata["isAccessibleForFree"]); err == nil {
+		isAccessibleForFree = &free
+	}
+
 	return Article{
-		Title:         validTitle,
-		Byline:        validByline,
-		Node:          readableNode,
-		Content:       finalHTMLContent,
-		TextContent:   finalTextContent,
-		Length:        charCount(finalTextContent),
-		Excerpt:       validExcerpt,
-		SiteName:      metadata["siteName"],
-		Image:         metadata["image"],
-		Favicon:       metadata["favicon"],
-		Language:      ps.articleLang,
-		PublishedTime: publishedTime,
-		ModifiedTime:  modifiedTime,
+		URL:                 strPageURL,
+		Title:               validTitle,
+		Byline:              validByline,
+		Node:                readableNode,
+		Content:             finalHTMLContent,
+		TextContent:         finalTextContent,
+		Length:              charCount(finalTextContent),
+		Excerpt:             validExcerpt,
+		SiteName:            metadata["siteName"],
+		Image:               metadata["image"],
+		Favicon:             metadata["favicon"],
+		CanonicalURL:        metadata["canonicalURL"],
+		Language:            language,
+		Dir:                 ps.articleDir,
+		PublishedTime:       publishedTime,
+		ModifiedTime:        modifiedTime,
+		Keywords:            keywords,
+		Section:             metadata["section"],
+		DeclaredWordCount:   declaredWordCount,
+		IsAccessibleForFree: isAccessibleForFree,
+		MainEntityOfPage:    metadata["mainEntityOfPage"],
+		Authors:             authors,
+		Found:               articleContent != nil,
+		Confidence:          ps.articleConfidence,
+		Extraction:          ps.extraction,
+		Comments:            comments,
+		Posts:               posts,
+		Outline:             outline,
+		WordCount:           nWords,
+		ReadingTime:         ps.getReadingTime(nWords),
+		LanguageTag:         languageTag,
+		LanguageSource:      languageSource,
 	}, nil
 }
 
+// getReadingTime estimates how long it takes to read the words.
+func (ps *Parser) getReadingTime(nWords int) time.Duration {
+	wordsPerMinute := ps.WordsPerMinute
+	if wordsPerMinute <= 0 {
+		wordsPerMinute = defaultWordsPerMinute
+	}
+
+	readingTime := time.Duration(nWords) * time.Minute / time.Duration(wordsPerMinute)
+	return readingTime.Round(time.Second)
+}
+
 // getDate tries to get a date from metadata, and parse it using a list of known formats.
 func (ps *Parser) getDate(metadata map[string]string, fieldName string) *time.Time {
 	dateStr, ok := metadata[fieldName]
@@ -149,7 +293,7 @@ func (ps *Parser) getDate(metadata map[string]string, fieldName string) *time.Ti
 func (ps *Parser) getParsedDate(dateStr string) *time.Time {
 	d, err := dateparse.ParseAny(dateStr)
 	if err != nil {
-		ps.logf("failed to parse date \"%s\": %v\n", dateStr, err)
+		ps.traceInfo("failed to parse date \"%s\": %v", dateStr, err)
 		return nil
 	}
 	return &d
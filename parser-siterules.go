@@ -0,0 +1,155 @@
+package readability
+
+import (
+	"github.com/andybalholm/cascadia"
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// keepAttr marks the elements that match the keep selectors of a site rule.
+const keepAttr = "data-readability-keep"
+
+var keepSelector = cascadia.MustCompile("[" + keepAttr + "]")
+
+// applySiteRuleMetadata overrides the metadata using the title, byline
+// and date selectors of the site rule.
+func (ps *Parser) applySiteRuleMetadata(rule *SiteRule, metadata map[string]string) {
+	if rule == nil {
+		return
+	}
+
+	if node := ps.querySiteRule(rule.Title); node != nil {
+		if title := ps.getInnerText(node, true); title != "" {
+			metadata["title"] = title
+		}
+	}
+
+	if node := ps.querySiteRule(rule.Byline); node != nil {
+		if byline := ps.getInnerText(node, true); byline != "" {
+			metadata["byline"] = byline
+		}
+	}
+
+	if node := ps.querySiteRule(rule.Date); node != nil {
+		date := strOr(
+			dom.GetAttribute(node, "datetime"),
+			dom.GetAttribute(node, "content"),
+			ps.getInnerText(node, true))
+		if date != "" {
+			metadata["publishedTime"] = date
+		}
+	}
+}
+
+// applySiteRuleSelectors removes the elements that match the remove
+// selectors of the site rule, then marks the elements that match its
+// keep selectors so they won't be removed later.
+func (ps *Parser) applySiteRuleSelectors(rule *SiteRule) {
+	if rule == nil {
+		return
+	}
+
+	for _, selector := range rule.Remove {
+		ps.removeNodes(dom.QuerySelectorAll(ps.doc, selector), "removed by site rule", nil)
+	}
+
+	for _, selector := range rule.Keep {
+		for _, node := range dom.QuerySelectorAll(ps.doc, selector) {
+			dom.SetAttribute(node, keepAttr, "true")
+			ps.hasKeptNodes = true
+		}
+	}
+}
+
+// grabSiteRuleContent uses the elements that match the content selector
+// of the site rule as the article content. It returns nil if the selector
+// doesn't match anything, in which case `grabArticle` should be used.
+func (ps *Parser) grabSiteRuleContent(rule *SiteRule) *html.Node {
+	if rule == nil || rule.Content == "" {
+		return nil
+	}
+
+	nodes := dom.QuerySelectorAll(ps.doc, rule.Content)
+	if len(nodes) == 0 {
+		ps.traceInfo("site rule content %q doesn't match, fall back to grabArticle", rule.Content)
+		return nil
+	}
+
+	// Copy the matched elements, so the document is kept untouched in
+	// case we need to fall back. Elements that are nested inside another
+	// matched element are already copied along with their ancestor.
+	page := dom.CreateElement("div")
+	dom.SetAttribute(page, "id", "readability-page-1")
+	dom.SetAttribute(page, "class", "page")
+
+	for _, node := range nodes {
+		nested := ps.someNode(ps.getNodeAncestors(node, 0), func(ancestor *html.Node) bool {
+			return indexOfNode(nodes, ancestor) != -1
+		})
+
+		if !nested {
+			ps.trace(TraceEvent{Action: TraceSelect, Node: node, Reason: "site rule content"})
+			dom.AppendChild(page, dom.Clone(node, true))
+		}
+	}
+
+	articleContent := dom.CreateElement("div")
+	dom.AppendChild(articleContent, page)
+
+	if !ps.prepSelectedContent(articleContent, nodes[0]) {
+		ps.traceInfo("site rule content %q is empty, fall back to grabArticle", rule.Content)
+		return nil
+	}
+
+	return articleContent
+}
+
+// prepSelectedContent cleans the article content that is chosen without
+// scoring, e.g. by a site rule, then fills the article direction and the
+// extraction info from it. The direction is taken from the source node or
+// its ancestors. It returns false if the content is empty.
+func (ps *Parser) prepSelectedContent(articleContent, source *html.Node) bool {
+	// The content is chosen explicitly, so only the basic cleaning is done.
+	firstFlags := ps.flags
+	ps.flags = flags{}
+	ps.prepArticle(articleContent)
+
+	textLength := charCount(ps.getInnerText(articleContent, true))
+	if textLength == 0 {
+		ps.flags = firstFlags
+		return false
+	}
+
+	// Find out text direction from the source node.
+	ancestors := append([]*html.Node{source}, ps.getNodeAncestors(source, 0)...)
+	ps.setArticleDir(ancestors)
+
+	ps.articleConfidence = ps.getConfidence(articleContent, 0)
+	ps.extraction = ps.getExtractionInfo(articleContent, parseAttempt{flags: ps.flags}, []int{textLength}, false)
+	return true
+}
+
+// querySiteRule returns the first element in document that matches the
+// selector, or nil if the selector is empty.
+func (ps *Parser) querySiteRule(selector string) *html.Node {
+	if selector == "" {
+		return nil
+	}
+	return dom.QuerySelector(ps.doc, selector)
+}
+
+// isKeptNode checks whether the node is marked to be kept by a site rule,
+// or is inside a marked element. The ancestors of a marked element are
+// not protected, so its wrappers can still be removed.
+func (ps *Parser) isKeptNode(node *html.Node) bool {
+	if !ps.hasKeptNodes {
+		return false
+	}
+
+	for ; node != nil; node = node.Parent {
+		if node.Type == html.ElementNode && keepSelector.Match(node) {
+			return true
+		}
+	}
+	return false
+}
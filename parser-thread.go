@@ -0,0 +1,142 @@
+package readability
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+var (
+	rxThreadPost      = regexp.MustCompile(`(?i)post|answer|question|message|entry|reply|comment|topic`)
+	rxThreadPostTypes = regexp.MustCompile(`^(Question|Answer|Comment|DiscussionForumPosting|SocialMediaPosting)$`)
+)
+
+// Post is a post of a forum thread or a Q&A page.
+type Post struct {
+	Author        Author
+	PublishedTime *time.Time
+	Content       string
+	TextContent   string
+}
+
+// grabThread extracts every post of a forum thread or Q&A page, in the
+// order they appear in the document. It returns the article content that
+// combines all posts, each one wrapped in its own `article` element, or
+// nil if the page doesn't look like a thread.
+func (ps *Parser) grabThread() (*html.Node, []Post) {
+	items := ps.findThreadPosts()
+	if len(items) < 2 {
+		ps.traceInfo("no repeated posts found, fall back to grabArticle")
+		return nil, nil
+	}
+
+	page := dom.CreateElement("div")
+	dom.SetAttribute(page, "id", "readability-page-1")
+	dom.SetAttribute(page, "class", "page")
+
+	var posts []Post
+	for _, item := range items {
+		post, contentNode, ok := ps.readPostNode(item)
+		if !ok {
+			continue
+		}
+
+		ps.trace(TraceEvent{Action: TraceSelect, Node: item, Reason: "thread post"})
+		postNode := dom.CreateElement("article")
+		for _, child := range dom.ChildNodes(contentNode) {
+			dom.AppendChild(postNode, child)
+		}
+
+		dom.AppendChild(page, postNode)
+		posts = append(posts, post)
+	}
+
+	if len(posts) < 2 {
+		ps.traceInfo("thread has less than two posts, fall back to grabArticle")
+		return nil, nil
+	}
+
+	articleContent := dom.CreateElement("div")
+	dom.AppendChild(articleContent, page)
+	if !ps.prepSelectedContent(articleContent, items[0]) {
+		ps.traceInfo("thread posts are empty, fall back to grabArticle")
+		return nil, nil
+	}
+
+	return articleContent, posts
+}
+
+// findThreadPosts returns the post nodes of the thread. The posts that are
+// marked by schema.org microdata (e.g. `Question` and `Answer`) are used if
+// possible, in document order even when a post is nested inside another
+// one, like the answers inside the question on some Q&A pages. Otherwise
+// the posts are the biggest group of repeated siblings whose class hints
+// that they are posts, as long as they contain most of the text in the
+// page.
+func (ps *Parser) findThreadPosts() []*html.Node {
+	var posts []*html.Node
+	for _, item := range dom.QuerySelectorAll(ps.doc, "[itemscope][itemtype]") {
+		if hasMicrodataType(item, rxThreadPostTypes) {
+			posts = append(posts, item)
+		}
+	}
+
+	if len(posts) >= 2 {
+		return posts
+	}
+
+	body := dom.QuerySelector(ps.doc, "body")
+	if body == nil {
+		return nil
+	}
+
+	var bestGroup []*html.Node
+	bestLength := 0
+	for _, parent := range dom.GetElementsByTagName(body, "*") {
+		groups := map[string][]*html.Node{}
+		for _, child := range dom.Children(parent) {
+			if signature := commentSignature(child); rxThreadPost.MatchString(signature) {
+				groups[signature] = append(groups[signature], child)
+			}
+		}
+
+		for _, group := range groups {
+			if len(group) < 2 {
+				continue
+			}
+
+			groupLength := 0
+			for _, node := range group {
+				groupLength += charCount(ps.getInnerText(node, true))
+			}
+
+			if groupLength > bestLength {
+				bestGroup, bestLength = group, groupLength
+			}
+		}
+	}
+
+	if bestLength*2 < charCount(ps.getInnerText(body, true)) {
+		return nil
+	}
+
+	return bestGroup
+}
+
+// hasMicrodataType checks whether the node is a microdata item with a
+// type that matches the regex, e.g. `Answer` for schema.org/Answer.
+func hasMicrodataType(node *html.Node, rxTypes *regexp.Regexp) bool {
+	if !dom.HasAttribute(node, "itemscope") {
+		return false
+	}
+
+	for _, strType := range strings.Fields(dom.GetAttribute(node, "itemtype")) {
+		if rxTypes.MatchString(microdataName(strType)) {
+			return true
+		}
+	}
+	return false
+}
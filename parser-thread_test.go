@@ -0,0 +1,125 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_ThreadMode(t *testing.T) {
+	makePost := func(author, date, text string) string {
+		return `<div class="post"><div class="post-author"><a href="/member/` + author + `">` + author + `</a></div>` +
+			`<time datetime="` + date + `">` + date + `</time>` +
+			`<div class="post-body"><p>` + strings.Repeat(text+" ", 15) + `</p></div></div>`
+	}
+
+	source := `<html lang="en"><body><nav><a href="/">Forum</a></nav>` +
+		`<div id="thread">` +
+		makePost("alice", "2024-05-01T10:00:00Z", "How do I configure the frobnicator properly?") +
+		makePost("bob", "2024-05-01T11:00:00Z", "You need to set the frobnication level first, then restart.") +
+		makePost("carol", "2024-05-02T09:00:00Z", "Thanks, setting the level fixed it for me too.") +
+		`</div></body></html>`
+
+	parser := NewParser()
+	parser.ThreadMode = true
+	article, err := parser.Parse(strings.NewReader(source), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	expectedAuthors := []string{"alice", "bob", "carol"}
+	if len(article.Posts) != len(expectedAuthors) {
+		t.Fatalf("want %d posts, got %+v", len(expectedAuthors), article.Posts)
+	}
+
+	for i, author := range expectedAuthors {
+		post := article.Posts[i]
+		if post.Author.Name != author || post.PublishedTime == nil || post.TextContent == "" {
+			t.Errorf("unexpected post %d: %+v", i, post)
+		}
+	}
+
+	if article.Posts[1].Author.URL != "http://fakehost/member/bob" {
+		t.Errorf("unexpected author URL: %s", article.Posts[1].Author.URL)
+	}
+
+	for _, text := range []string{"frobnicator", "restart", "fixed it"} {
+		if !strings.Contains(article.TextContent, text) {
+			t.Errorf("combined content doesn't contain %q", text)
+		}
+	}
+
+	if n := strings.Count(article.Content, "<article>"); n != 3 {
+		t.Errorf("want 3 article elements in combined content, got %d", n)
+	}
+
+	// Normal article is parsed as usual
+	content := loremParagraph(20)
+	article, err = parser.Parse(strings.NewReader(`<html><body><article>`+content+content+`</article></body></html>`), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if len(article.Posts) != 0 || !strings.Contains(article.TextContent, "Lorem ipsum") {
+		t.Errorf("normal article is parsed as thread: %+v", article.Posts)
+	}
+}
+
+func Test_ThreadModeMicrodata(t *testing.T) {
+	makePost := func(itemType, author, text string) string {
+		return `<div class="` + strings.ToLower(itemType) + `" itemscope itemtype="https://schema.org/` + itemType + `">` +
+			`<div itemprop="text"><p>` + strings.Repeat(text+" ", 10) + `</p></div>` +
+			`<div class="user-info" itemprop="author" itemscope itemtype="https://schema.org/Person">` +
+			`<span itemprop="name">` + author + `</span></div></div>`
+	}
+
+	source := `<html><body><div id="mainbar" itemscope itemtype="https://schema.org/QAPage">` +
+		makePost("Question", "Alice", "Why is the sky blue?") +
+		`<div id="answers">` +
+		makePost("Answer", "Bob", "Because of Rayleigh scattering.") +
+		makePost("Answer", "Carol", "Shorter wavelengths scatter more.") +
+		`</div></div></body></html>`
+
+	parser := NewParser()
+	parser.ThreadMode = true
+	article, err := parser.Parse(strings.NewReader(source), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if len(article.Posts) != 3 {
+		t.Fatalf("want 3 posts, got %+v", article.Posts)
+	}
+
+	if article.Posts[0].Author.Name != "Alice" || !strings.HasPrefix(article.Posts[0].TextContent, "Why is the sky blue?") ||
+		article.Posts[2].Author.Name != "Carol" {
+		t.Errorf("unexpected posts: %+v", article.Posts)
+	}
+
+	// The answers might be nested inside the question
+	source = `<html><body><div id="mainbar" itemscope itemtype="https://schema.org/QAPage">` +
+		strings.TrimSuffix(makePost("Question", "Alice", "Why is the sky blue?"), "</div>") +
+		`<div id="answers">` +
+		makePost("Answer", "Bob", "Because of Rayleigh scattering.") +
+		makePost("Answer", "Carol", "Shorter wavelengths scatter more.") +
+		`</div></div></div></body></html>`
+
+	article, err = parser.Parse(strings.NewReader(source), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	expectedAuthors := []string{"Alice", "Bob", "Carol"}
+	if len(article.Posts) != len(expectedAuthors) {
+		t.Fatalf("nested answers, want %d posts, got %+v", len(expectedAuthors), article.Posts)
+	}
+
+	for i, author := range expectedAuthors {
+		if post := article.Posts[i]; post.Author.Name != author {
+			t.Errorf("nested answers, want post %d by %s, got %+v", i, author, post)
+		}
+	}
+
+	if question := article.Posts[0].TextContent; strings.Contains(question, "Rayleigh") {
+		t.Errorf("nested answers are part of the question: %q", question)
+	}
+}
@@ -1,10 +1,10 @@
 package readability
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	shtml "html"
-	"log"
 	"math"
 	nurl "net/url"
 	"regexp"
@@ -26,8 +26,8 @@ var (
 	rxWhitespace           = regexp.MustCompile(`(?i)^\s*$`)
 	rxHasContent           = regexp.MustCompile(`(?i)\S$`)
 	rxHashURL              = regexp.MustCompile(`(?i)^#.+`)
-	rxPropertyPattern      = regexp.MustCompile(`(?i)\s*(dc|dcterm|og|article|twitter)\s*:\s*(author|creator|description|title|site_name|published_time|modified_time|image\S*)\s*`)
-	rxNamePattern          = regexp.MustCompile(`(?i)^\s*(?:(dc|dcterm|article|og|twitter|weibo:(article|webpage))\s*[\.:]\s*)?(author|creator|description|title|site_name|published_time|modified_time|image)\s*$`)
+	rxPropertyPattern      = regexp.MustCompile(`(?i)\s*(dc|dcterm|og|article|twitter)\s*:\s*(author|creator|description|title|site_name|published_time|modified_time|section|url|image\S*)\s*`)
+	rxNamePattern          = regexp.MustCompile(`(?i)^\s*(?:(dc|dcterm|article|og|twitter|weibo:(article|webpage))\s*[\.:]\s*)?(author|creator|description|title|site_name|published_time|modified_time|keywords|image)\s*$`)
 	rxTitleSeparator       = regexp.MustCompile(`(?i) [\|\-\\/>»] `)
 	rxTitleHierarchySep    = regexp.MustCompile(`(?i) [\\/>»] `)
 	rxTitleRemoveFinalPart = regexp.MustCompile(`(?i)(.*)[\|\-\\/>»] .*`)
@@ -46,6 +46,14 @@ var (
 	rxJsonLdArticleTypes   = regexp.MustCompile(`(?i)^Article|AdvertiserContentArticle|NewsArticle|AnalysisNewsArticle|AskPublicNewsArticle|BackgroundNewsArticle|OpinionNewsArticle|ReportageNewsArticle|ReviewNewsArticle|Report|SatiricalArticle|ScholarlyArticle|MedicalScholarlyArticle|SocialMediaPosting|BlogPosting|LiveBlogPosting|DiscussionForumPosting|TechArticle|APIReference$`)
 	rxCDATA                = regexp.MustCompile(`^\s*<!\[CDATA\[|\]\]>\s*$`)
 	rxSchemaOrg            = regexp.MustCompile(`(?i)^https?\:\/\/schema\.org\/?$`)
+	rxExtraneous           = regexp.MustCompile(`(?i)print|archive|comment|discuss|e[\-]?mail|share|reply|all|login|sign|single|utility`)
+	rxNextLink             = regexp.MustCompile(`(?i)(next|weiter|continue|>([^\|]|$)|»([^\|]|$))`)
+	rxPrevLink             = regexp.MustCompile(`(?i)(prev|earl|old|new|<|«)`)
+	rxPaging               = regexp.MustCompile(`(?i)pag(e|ing|inat)`)
+	rxFirstOrLast          = regexp.MustCompile(`(?i)(first|last)`)
+	rxPagingURL            = regexp.MustCompile(`(?i)p(a|g|ag)?(e|ing|ination)?(=|/)[0-9]{1,2}|(page|paging)`)
+	rxURLPageNumber        = regexp.MustCompile(`(?i)((_|-)?p[a-z]*|(_|-))[0-9]{1,2}$`)
+	rxLeadingNumber        = regexp.MustCompile(`^\s*(\d+)`)
 )
 
 // Constants that used by readability.
@@ -63,6 +71,9 @@ var (
 		"sup", "textarea", "time", "var", "wbr"}
 )
 
+// defaultWordsPerMinute is the average silent reading speed of adults.
+const defaultWordsPerMinute = 238
+
 // flags is flags that used by parser.
 type flags struct {
 	stripUnlikelys     bool
@@ -72,25 +83,79 @@ type flags struct {
 
 // parseAttempt is container for the result of previous parse attempts.
 type parseAttempt struct {
-	articleContent *html.Node
-	textLength     int
+	articleContent    *html.Node
+	textLength        int
+	topCandidateScore float64
+	runnerUpScores    []float64
+	flags             flags
 }
 
 // Article is the final readable content.
+//
+// Language is the raw language of the article, as it's written in the lang
+// attribute or the metadata of the page. It's kept for compatibility, and
+// superseded by LanguageTag which is the normalized BCP 47 language that
+// is resolved from the html, headers, metadata and text, in that order.
+// The source of LanguageTag is reported in LanguageSource.
 type Article struct {
-	Title         string
-	Byline        string
-	Node          *html.Node
-	Content       string
-	TextContent   string
-	Length        int
-	Excerpt       string
-	SiteName      string
-	Image         string
-	Favicon       string
-	Language      string
-	PublishedTime *time.Time
-	ModifiedTime  *time.Time
+	URL                 string
+	Title               string
+	Byline              string
+	Node                *html.Node
+	Content             string
+	TextContent         string
+	Length              int
+	Excerpt             string
+	SiteName            string
+	Image               string
+	Favicon             string
+	CanonicalURL        string
+	Language            string
+	Dir                 string
+	PublishedTime       *time.Time
+	ModifiedTime        *time.Time
+	Keywords            []string
+	Section             string
+	DeclaredWordCount   int
+	IsAccessibleForFree *bool
+	MainEntityOfPage    string
+	Authors             []Author
+	Encoding            string
+	Found               bool
+	Confidence          float64
+	Extraction          ExtractionInfo
+	Comments            []Comment
+	Posts               []Post
+	Outline             []Heading
+	WordCount           int
+	ReadingTime         time.Duration
+	LanguageTag         string
+	LanguageSource      LanguageSource
+}
+
+// ExtractionInfo describes how the article content was extracted, which
+// is useful to find extractions that need to be reviewed.
+type ExtractionInfo struct {
+	// TopCandidateScore is the content score of the final top candidate.
+	TopCandidateScore float64
+	// RunnerUpScores is the content scores of the other top candidates,
+	// sorted from the highest. Scores that are close to the top candidate
+	// mean the parser had a hard time choosing the article.
+	RunnerUpScores []float64
+	// LinkDensity is the link density of the article content.
+	LinkDensity float64
+	// StripUnlikelys, UseWeightClasses and CleanConditionally are the
+	// flags that were enabled in the attempt that is used as the article.
+	// Each failed attempt disables one of them.
+	StripUnlikelys     bool
+	UseWeightClasses   bool
+	CleanConditionally bool
+	// AttemptTextLengths is the text length of each attempt, in the order
+	// they were made.
+	AttemptTextLengths []int
+	// UsedFallback is true when every attempt was shorter than
+	// `Parser.CharThresholds`, so the longest attempt is used instead.
+	UsedFallback bool
 }
 
 // Parser is the parser that parses the page to get the readable content.
@@ -110,24 +175,90 @@ type Parser struct {
 	KeepClasses bool
 	// TagsToScore is element tags to score by default.
 	TagsToScore []string
-	// Debug determines if the log should be printed or not. Default: false.
+	// Debug determines if the trace events should be printed to stderr
+	// when `Tracer` is not set. Default: false.
 	Debug bool
+	// Tracer receives the structured events for every removal, conversion
+	// and scoring decision made by the parser. Default: nil (no tracing)
+	Tracer Tracer
 	// DisableJSONLD determines if metadata in JSON+LD will be extracted
 	// or not. Default: false.
 	DisableJSONLD bool
 	// AllowedVideoRegex is a regular expression that matches video URLs that should be
 	// allowed to be included in the article content. If undefined, it will use default filter.
 	AllowedVideoRegex *regexp.Regexp
-
-	doc             *html.Node
-	documentURI     *nurl.URL
-	articleTitle    string
-	articleByline   string
-	articleDir      string
-	articleSiteName string
-	articleLang     string
-	attempts        []parseAttempt
-	flags           flags
+	// RequireContent makes the parser returns `ErrNoContent` when it can't
+	// find any article content. By default, the article is still returned
+	// with its metadata but without any content. Default: false.
+	RequireContent bool
+	// CharsetHint is the character encoding of the input that is declared
+	// outside the document, e.g. by the Content-Type header. It's preferred
+	// over the encoding declared in the meta tags, but not over the byte
+	// order mark. `FromURL` sets it from the response header.
+	CharsetHint string
+	// LanguageHint is the language of the input that is declared outside
+	// the document, e.g. by the Content-Language header. It's used to
+	// resolve `Article.LanguageTag` when the html element doesn't have lang
+	// attribute. `FromURL` sets it from the response header.
+	LanguageHint string
+	// ForceCharset is the character encoding that is always used to decode
+	// the input, whatever the document and the server declare.
+	ForceCharset string
+	// MaxPages is the max number of pages that will be stitched together for
+	// articles that are split into multiple pages. Next pages are only looked
+	// up when it's bigger than 1, in which case each page is appended as its
+	// own `readability-page-N` div and `Article.Node` is the div that wraps
+	// all of them. Default: 0 (disabled)
+	MaxPages int
+	// PreferLargestImage determines if the largest image should be used as
+	// `Article.Image` when the page declares several og:image along with
+	// their og:image:width. Default: false (the last og:image is used)
+	PreferLargestImage bool
+	// PageFetcher is used to download the next pages of multi-page article.
+	// If undefined, the pages are downloaded using `http.DefaultClient`.
+	PageFetcher Fetcher
+	// Classifiers are the class and id heuristics, e.g. to find the nodes
+	// that are unlikely to be part of the article. Use `ExtendClassifier`
+	// to add more patterns. Default: `DefaultClassifiers()`
+	Classifiers Classifiers
+	// SiteRules are the custom extraction rules for specific sites. The
+	// first rule whose host matches the page URL is used. See `SiteRule`.
+	SiteRules []SiteRule
+	// ExtractComments determines if the comment section of the page should
+	// be extracted as `Article.Comments`. The comments are taken from the
+	// JSON-LD if possible, or else from the document. Default: false.
+	ExtractComments bool
+	// ThreadMode determines if the page should be parsed as a forum thread
+	// or Q&A page, which has many posts with similar weight. Every post is
+	// extracted as `Article.Posts`, and `Article.Content` combines all of
+	// them. If the page doesn't have repeated posts, the article is parsed
+	// as usual. Default: false.
+	ThreadMode bool
+	// GenerateOutline determines if the headings of the article should be
+	// collected as `Article.Outline`. Headings without id get an unique id
+	// in the article content, so they can be used as anchors. Default: false.
+	GenerateOutline bool
+	// WordsPerMinute is the reading speed that is used to estimate
+	// `Article.ReadingTime`. Since every Chinese and Japanese char is
+	// counted as a word, it's also used as chars per minute for those
+	// languages. Default: 238
+	WordsPerMinute int
+
+	doc               *html.Node
+	documentURI       *nurl.URL
+	baseURI           *nurl.URL
+	articleTitle      string
+	articleByline     string
+	articleConfidence float64
+	extraction        ExtractionInfo
+	jsonLdAuthors     []Author
+	articleDir        string
+	articleSiteName   string
+	articleLang       string
+	attempts          []parseAttempt
+	flags             flags
+	hasKeptNodes      bool
+	maxBodySize       int64
 }
 
 // NewParser returns new Parser which set up with default value.
@@ -140,6 +271,8 @@ func NewParser() Parser {
 		KeepClasses:       false,
 		TagsToScore:       []string{"section", "h2", "h3", "h4", "h5", "h6", "p", "td", "pre"},
 		Debug:             false,
+		Classifiers:       DefaultClassifiers(),
+		WordsPerMinute:    defaultWordsPerMinute,
 	}
 }
 
@@ -162,12 +295,22 @@ func (ps *Parser) postProcessContent(articleContent *html.Node) {
 
 // removeNodes iterates over a NodeList, calls `filterFn` for each node
 // and removes node if function returned `true`. If function is not
-// passed, removes all the nodes in node list.
-func (ps *Parser) removeNodes(nodeList []*html.Node, filterFn func(*html.Node) bool) {
+// passed, removes all the nodes in node list. The removal is traced
+// using `reason`, unless it's empty which means `filterFn` traces
+// the removal by itself. Nodes that are kept by site rule are never
+// removed.
+func (ps *Parser) removeNodes(nodeList []*html.Node, reason string, filterFn func(*html.Node) bool) {
 	for i := len(nodeList) - 1; i >= 0; i-- {
 		node := nodeList[i]
 		parentNode := node.Parent
-		if parentNode != nil && (filterFn == nil || filterFn(node)) {
+		if parentNode == nil || ps.isKeptNode(node) {
+			continue
+		}
+
+		if filterFn == nil || filterFn(node) {
+			if reason != "" {
+				ps.trace(TraceEvent{Action: TraceRemove, Node: node, Reason: reason})
+			}
 			parentNode.RemoveChild(node)
 		}
 	}
@@ -175,9 +318,10 @@ func (ps *Parser) removeNodes(nodeList []*html.Node, filterFn func(*html.Node) b
 
 // replaceNodeTags iterates over a NodeList, and calls setNodeTag for
 // each node.
-func (ps *Parser) replaceNodeTags(nodeList []*html.Node, newTagName string) {
+func (ps *Parser) replaceNodeTags(nodeList []*html.Node, newTagName string, reason string) {
 	for i := len(nodeList) - 1; i >= 0; i-- {
 		node := nodeList[i]
+		ps.trace(TraceEvent{Action: TraceConvert, Node: node, Tag: newTagName, Reason: reason})
 		ps.setNodeTag(node, newTagName)
 	}
 }
@@ -253,7 +397,8 @@ func (ps *Parser) cleanClasses(node *html.Node) {
 }
 
 // fixRelativeURIs converts each <a> and <img> uri in the given element
-// to an absolute URI, ignoring #ref URIs.
+// to an absolute URI, ignoring #ref URIs. The URIs are resolved against
+// the base URI of the document, which respects its <base> element.
 func (ps *Parser) fixRelativeURIs(articleContent *html.Node) {
 	links := ps.getAllNodesWithTag(articleContent, "a")
 	ps.forEachNode(links, func(link *html.Node, _ int) {
@@ -282,7 +427,7 @@ func (ps *Parser) fixRelativeURIs(articleContent *html.Node) {
 				dom.ReplaceChild(link.Parent, container, link)
 			}
 		} else {
-			newHref := toAbsoluteURI(href, ps.documentURI)
+			newHref := ps.resolveURI(href)
 			if newHref == "" {
 				dom.RemoveAttribute(link, "href")
 			} else {
@@ -298,19 +443,19 @@ func (ps *Parser) fixRelativeURIs(articleContent *html.Node) {
 		srcset := dom.GetAttribute(media, "srcset")
 
 		if src != "" {
-			newSrc := toAbsoluteURI(src, ps.documentURI)
+			newSrc := ps.resolveURI(src)
 			dom.SetAttribute(media, "src", newSrc)
 		}
 
 		if poster != "" {
-			newPoster := toAbsoluteURI(poster, ps.documentURI)
+			newPoster := ps.resolveURI(poster)
 			dom.SetAttribute(media, "poster", newPoster)
 		}
 
 		if srcset != "" {
 			newSrcset := rxSrcsetURL.ReplaceAllStringFunc(srcset, func(s string) string {
 				p := rxSrcsetURL.FindStringSubmatch(s)
-				return toAbsoluteURI(p[1], ps.documentURI) + p[2] + p[3]
+				return ps.resolveURI(p[1]) + p[2] + p[3]
 			})
 
 			dom.SetAttribute(media, "srcset", newSrcset)
@@ -328,12 +473,15 @@ func (ps *Parser) simplifyNestedElements(articleContent *html.Node) {
 		if node.Parent != nil && (nodeTagName == "div" || nodeTagName == "section") &&
 			!strings.HasPrefix(nodeID, "readability") {
 			if ps.isElementWithoutContent(node) {
+				ps.trace(TraceEvent{Action: TraceRemove, Node: node, Reason: "nested element without content"})
 				node = ps.removeAndGetNext(node)
 				continue
 			}
 
 			if ps.hasSingleTagInsideElement(node, "div") || ps.hasSingleTagInsideElement(node, "section") {
 				child := dom.Children(node)[0]
+				ps.trace(TraceEvent{Action: TraceConvert, Node: node, Tag: dom.TagName(child),
+					Reason: "element replaced by its only child"})
 				for _, attr := range node.Attr {
 					dom.SetAttribute(child, attr.Key, attr.Val)
 				}
@@ -434,13 +582,13 @@ func (ps *Parser) prepDocument() {
 	ps.removeComments(doc)
 
 	// Remove all style tags in head
-	ps.removeNodes(dom.GetElementsByTagName(doc, "style"), nil)
+	ps.removeNodes(dom.GetElementsByTagName(doc, "style"), "style element", nil)
 
 	if nodes := dom.GetElementsByTagName(doc, "body"); len(nodes) > 0 && nodes[0] != nil {
 		ps.replaceBrs(nodes[0])
 	}
 
-	ps.replaceNodeTags(dom.GetElementsByTagName(doc, "font"), "span")
+	ps.replaceNodeTags(dom.GetElementsByTagName(doc, "font"), "span", "obsolete font element")
 }
 
 // nextNode finds the next element, starting from the given node, and
@@ -481,6 +629,7 @@ func (ps *Parser) replaceBrs(elem *html.Node) {
 
 			replaced = true
 			brSibling := next.NextSibling
+			ps.trace(TraceEvent{Action: TraceRemove, Node: next, Reason: "chain of <br> elements"})
 			next.Parent.RemoveChild(next)
 			next = brSibling
 		}
@@ -489,6 +638,7 @@ func (ps *Parser) replaceBrs(elem *html.Node) {
 		// all sibling nodes as children of the <p> until we hit another <br>
 		// chain.
 		if replaced {
+			ps.trace(TraceEvent{Action: TraceConvert, Node: br, Tag: "p", Reason: "chain of <br> elements"})
 			p := dom.CreateElement("p")
 			dom.ReplaceChild(br.Parent, p, br)
 
@@ -517,6 +667,7 @@ func (ps *Parser) replaceBrs(elem *html.Node) {
 			}
 
 			if dom.TagName(p.Parent) == "p" {
+				ps.trace(TraceEvent{Action: TraceConvert, Node: p.Parent, Tag: "div", Reason: "paragraph inside paragraph"})
 				ps.setNodeTag(p.Parent, "div")
 			}
 		}
@@ -559,7 +710,11 @@ func (ps *Parser) prepArticle(articleContent *html.Node) {
 
 	ps.forEachNode(dom.Children(articleContent), func(topCandidate *html.Node, _ int) {
 		ps.cleanMatchedNodes(topCandidate, func(node *html.Node, nodeClassID string) bool {
-			return rxShareElements.MatchString(nodeClassID) && charCount(dom.TextContent(node)) < shareElementThreshold
+			if rxShareElements.MatchString(nodeClassID) && charCount(dom.TextContent(node)) < shareElementThreshold {
+				ps.trace(TraceEvent{Action: TraceRemove, Node: node, Reason: "share element"})
+				return true
+			}
+			return false
 		})
 	})
 
@@ -577,10 +732,10 @@ func (ps *Parser) prepArticle(articleContent *html.Node) {
 	ps.cleanConditionally(articleContent, "div")
 
 	// Replace H1 with H2 as H1 should be only title that is displayed separately
-	ps.replaceNodeTags(ps.getAllNodesWithTag(articleContent, "h1"), "h2")
+	ps.replaceNodeTags(ps.getAllNodesWithTag(articleContent, "h1"), "h2", "only the title may be h1")
 
 	// Remove extra paragraphs
-	ps.removeNodes(dom.GetElementsByTagName(articleContent, "p"), func(p *html.Node) bool {
+	ps.removeNodes(dom.GetElementsByTagName(articleContent, "p"), "empty paragraph", func(p *html.Node) bool {
 		imgCount := len(dom.GetElementsByTagName(p, "img"))
 		embedCount := len(dom.GetElementsByTagName(p, "embed"))
 		objectCount := len(dom.GetElementsByTagName(p, "object"))
@@ -595,6 +750,7 @@ func (ps *Parser) prepArticle(articleContent *html.Node) {
 	ps.forEachNode(dom.GetElementsByTagName(articleContent, "br"), func(br *html.Node, _ int) {
 		next := ps.nextNode(br.NextSibling)
 		if next != nil && dom.TagName(next) == "p" {
+			ps.trace(TraceEvent{Action: TraceRemove, Node: br, Reason: "<br> before paragraph"})
 			br.Parent.RemoveChild(br)
 		}
 	})
@@ -616,6 +772,7 @@ func (ps *Parser) prepArticle(articleContent *html.Node) {
 					newTag = "p"
 				}
 
+				ps.trace(TraceEvent{Action: TraceConvert, Node: table, Tag: newTag, Reason: "single-cell table"})
 				ps.setNodeTag(cell, newTag)
 				dom.ReplaceChild(table.Parent, cell, table)
 			}
@@ -638,11 +795,19 @@ func (ps *Parser) initializeNode(node *html.Node) {
 		contentScore -= 5
 	}
 
+	ps.trace(TraceEvent{Action: TraceScore, Node: node, Score: contentScore, Reason: "initial score from tag and class weight"})
 	ps.setContentScore(node, contentScore)
 }
 
 // removeAndGetNext remove node and returns its next node.
 func (ps *Parser) removeAndGetNext(node *html.Node) *html.Node {
+	// Nodes that are kept by site rule are never removed, so just
+	// continue to their children.
+	if ps.isKeptNode(node) {
+		ps.trace(TraceEvent{Action: TraceSkip, Node: node, Reason: "kept by site rule"})
+		return ps.getNextNode(node, false)
+	}
+
 	nextNode := ps.getNextNode(node, true)
 	if node.Parent != nil {
 		node.Parent.RemoveChild(node)
@@ -715,7 +880,7 @@ func (ps *Parser) checkByline(node *html.Node, matchString string) bool {
 	rel := dom.GetAttribute(node, "rel")
 	itemprop := dom.GetAttribute(node, "itemprop")
 	nodeText := dom.TextContent(node)
-	if (rel == "author" || strings.Contains(itemprop, "author") || re2go.IsByline(matchString)) &&
+	if (rel == "author" || strings.Contains(itemprop, "author") || ps.isBylineClass(matchString)) &&
 		ps.isValidByline(nodeText) {
 		nodeText = strings.TrimSpace(nodeText)
 		nodeText = strings.Join(strings.Fields(nodeText), " ")
@@ -761,10 +926,15 @@ func (ps *Parser) getNodeAncestors(node *html.Node, maxDepth int) []*html.Node {
 // grabArticle uses a variety of metrics (content score, classname,
 // element types), find the content that is most likely to be the
 // stuff a user wants to read. Then return it wrapped up in a div.
-func (ps *Parser) grabArticle() *html.Node {
-	ps.log("**** GRAB ARTICLE ****")
-
+// The context is checked before each attempt, since on pathological
+// pages it might need several passes over the whole document.
+func (ps *Parser) grabArticle(ctx context.Context) (*html.Node, error) {
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		ps.traceInfo(traceGrabbingArticle)
 		doc := dom.Clone(ps.doc, true)
 
 		var page *html.Node
@@ -774,8 +944,9 @@ func (ps *Parser) grabArticle() *html.Node {
 
 		// We can't grab an article if we don't have a page!
 		if page == nil {
-			ps.log("no body found in document, abort")
-			return nil
+			ps.traceInfo("no body found in document, abort")
+			ps.traceInfo(traceAttemptFinished)
+			return nil, nil
 		}
 
 		// First, node prepping. Trash nodes that look cruddy (like ones
@@ -789,12 +960,8 @@ func (ps *Parser) grabArticle() *html.Node {
 		for node != nil {
 			matchString := dom.ClassName(node) + " " + dom.ID(node)
 
-			if dom.TagName(node) == "html" {
-				ps.articleLang = dom.GetAttribute(node, "lang")
-			}
-
 			if !ps.isProbablyVisible(node) {
-				ps.logf("removing hidden node: %q\n", matchString)
+				ps.trace(TraceEvent{Action: TraceRemove, Node: node, Reason: "hidden node"})
 				node = ps.removeAndGetNext(node)
 				continue
 			}
@@ -803,6 +970,7 @@ func (ps *Parser) grabArticle() *html.Node {
 			// and "role = dialog"
 			if dom.GetAttribute(node, "aria-modal") == "true" &&
 				dom.GetAttribute(node, "role") == "dialog" {
+				ps.trace(TraceEvent{Action: TraceRemove, Node: node, Reason: "modal dialog"})
 				node = ps.removeAndGetNext(node)
 				continue
 			}
@@ -810,13 +978,13 @@ func (ps *Parser) grabArticle() *html.Node {
 			// Check to see if this node is a byline, and remove it if
 			// it is true.
 			if ps.checkByline(node, matchString) {
+				ps.trace(TraceEvent{Action: TraceRemove, Node: node, Reason: "byline"})
 				node = ps.removeAndGetNext(node)
 				continue
 			}
 
 			if shouldRemoveTitleHeader && ps.headerDuplicatesTitle(node) {
-				ps.logf("removing header: %q duplicate of %q\n",
-					trim(dom.TextContent(node)), trim(ps.articleTitle))
+				ps.trace(TraceEvent{Action: TraceRemove, Node: node, Reason: "header duplicates the title"})
 				shouldRemoveTitleHeader = false
 				node = ps.removeAndGetNext(node)
 				continue
@@ -825,19 +993,18 @@ func (ps *Parser) grabArticle() *html.Node {
 			// Remove unlikely candidates
 			nodeTagName := dom.TagName(node)
 			if ps.flags.stripUnlikelys {
-				if re2go.IsUnlikelyCandidates(matchString) &&
-					!re2go.MaybeItsACandidate(matchString) &&
+				if ps.isUnlikelyCandidate(matchString) &&
 					!ps.hasAncestorTag(node, "table", 3, nil) &&
 					!ps.hasAncestorTag(node, "code", 3, nil) &&
 					nodeTagName != "body" && nodeTagName != "a" {
-					ps.logf("removing unlikely candidate: %q\n", matchString)
+					ps.trace(TraceEvent{Action: TraceRemove, Node: node, Reason: "unlikely candidate"})
 					node = ps.removeAndGetNext(node)
 					continue
 				}
 
 				role := dom.GetAttribute(node, "role")
 				if _, include := unlikelyRoles[role]; include {
-					ps.logf("removing content with role %q: %q\n", role, matchString)
+					ps.trace(TraceEvent{Action: TraceRemove, Node: node, Reason: "unlikely role " + role})
 					node = ps.removeAndGetNext(node)
 					continue
 				}
@@ -849,6 +1016,7 @@ func (ps *Parser) grabArticle() *html.Node {
 			case "div", "section", "header",
 				"h1", "h2", "h3", "h4", "h5", "h6":
 				if ps.isElementWithoutContent(node) {
+					ps.trace(TraceEvent{Action: TraceRemove, Node: node, Reason: "element without content"})
 					node = ps.removeAndGetNext(node)
 					continue
 				}
@@ -891,9 +1059,11 @@ func (ps *Parser) grabArticle() *html.Node {
 				// practice, paragraphs.
 				if ps.hasSingleTagInsideElement(node, "p") && ps.getLinkDensity(node) < 0.25 {
 					newNode := dom.Children(node)[0]
+					ps.trace(TraceEvent{Action: TraceConvert, Node: node, Tag: "p", Reason: "div with a single paragraph"})
 					node, _ = dom.ReplaceChild(node.Parent, newNode, node)
 					elementsToScore = append(elementsToScore, node)
 				} else if !ps.hasChildBlockElement(node) {
+					ps.trace(TraceEvent{Action: TraceConvert, Node: node, Tag: "p", Reason: "div without block elements"})
 					ps.setNodeTag(node, "p")
 					elementsToScore = append(elementsToScore, node)
 				}
@@ -914,6 +1084,7 @@ func (ps *Parser) grabArticle() *html.Node {
 			// If this paragraph is less than 25 characters, don't even count it.
 			innerText := ps.getInnerText(elementToScore, true)
 			if charCount(innerText) < 25 {
+				ps.trace(TraceEvent{Action: TraceSkip, Node: elementToScore, Reason: "too short to be scored"})
 				return
 			}
 
@@ -931,6 +1102,8 @@ func (ps *Parser) grabArticle() *html.Node {
 
 			// For every 100 characters in this paragraph, add another point. Up to 3 points.
 			contentScore += int(math.Min(math.Floor(float64(charCount(innerText))/100.0), 3.0))
+			ps.trace(TraceEvent{Action: TraceScore, Node: elementToScore, Score: float64(contentScore),
+				Reason: "paragraph score from commas and length"})
 
 			// Initialize and score ancestors.
 			ps.forEachNode(ancestors, func(ancestor *html.Node, level int) {
@@ -948,17 +1121,22 @@ func (ps *Parser) grabArticle() *html.Node {
 				// - grandparent:        2
 				// - great grandparent+: ancestor level * 3
 				var scoreDivider int
+				var reason string
 				switch level {
 				case 0:
 					scoreDivider = 1
+					reason = "score from child paragraph"
 				case 1:
 					scoreDivider = 2
+					reason = "score from grandchild paragraph"
 				default:
 					scoreDivider = level * 3
+					reason = "score from descendant paragraph"
 				}
 
 				ancestorScore := ps.getContentScore(ancestor)
 				ancestorScore += float64(contentScore) / float64(scoreDivider)
+				ps.trace(TraceEvent{Action: TraceScore, Node: ancestor, Score: ancestorScore, Reason: reason})
 				ps.setContentScore(ancestor, ancestorScore)
 			})
 		})
@@ -976,7 +1154,7 @@ func (ps *Parser) grabArticle() *html.Node {
 		for i := 0; i < len(candidates); i++ {
 			candidate := candidates[i]
 			candidateScore := ps.getContentScore(candidate) * (1 - ps.getLinkDensity(candidate))
-			ps.logf("candidate %q with score: %f\n", dom.OuterHTML(candidate), candidateScore)
+			ps.trace(TraceEvent{Action: TraceScore, Node: candidate, Score: candidateScore, Reason: "candidate scaled by link density"})
 			ps.setContentScore(candidate, candidateScore)
 		}
 
@@ -993,6 +1171,11 @@ func (ps *Parser) grabArticle() *html.Node {
 			topCandidates = candidates
 		}
 
+		var runnerUpScores []float64
+		for i := 1; i < len(topCandidates); i++ {
+			runnerUpScores = append(runnerUpScores, ps.getContentScore(topCandidates[i]))
+		}
+
 		var topCandidate, parentOfTopCandidate *html.Node
 		neededToCreateTopCandidate := false
 		if len(topCandidates) > 0 {
@@ -1009,12 +1192,12 @@ func (ps *Parser) grabArticle() *html.Node {
 			// Move everything (not just elements, also text nodes etc.)
 			// into the container so we even include text directly in the body:
 			for page.FirstChild != nil {
-				ps.logf("moving child out: %q\n", dom.OuterHTML(page.FirstChild))
 				dom.AppendChild(topCandidate, page.FirstChild)
 			}
 
 			dom.AppendChild(page, topCandidate)
 			ps.initializeNode(topCandidate)
+			ps.trace(TraceEvent{Action: TraceSelect, Node: topCandidate, Reason: "no candidate found, use the whole body"})
 		} else if topCandidate != nil {
 			// Find a better top candidate node if it contains (at least three)
 			// nodes which belong to `topCandidates` array and whose scores are
@@ -1098,6 +1281,8 @@ func (ps *Parser) grabArticle() *html.Node {
 			if !ps.hasContentScore(topCandidate) {
 				ps.initializeNode(topCandidate)
 			}
+
+			ps.trace(TraceEvent{Action: TraceSelect, Node: topCandidate, Reason: "top candidate"})
 		}
 
 		// Now that we have the top candidate, look through its siblings
@@ -1116,7 +1301,7 @@ func (ps *Parser) grabArticle() *html.Node {
 			sibling := siblings[s]
 			appendNode := false
 
-			if sibling == topCandidate {
+			if sibling == topCandidate || ps.isKeptNode(sibling) {
 				appendNode = true
 			} else {
 				contentBonus := float64(0)
@@ -1142,11 +1327,18 @@ func (ps *Parser) grabArticle() *html.Node {
 				}
 			}
 
-			if appendNode {
+			if !appendNode {
+				ps.trace(TraceEvent{Action: TraceSkip, Node: sibling, Reason: "sibling of top candidate below score threshold"})
+			} else {
+				if sibling != topCandidate {
+					ps.trace(TraceEvent{Action: TraceSelect, Node: sibling, Reason: "sibling of top candidate"})
+				}
+
 				// We have a node that isn't a common block level
 				// element, like a form or td tag. Turn it into a div
 				// so it doesn't get filtered out later by accident.
 				if indexOf(alterToDivExceptions, dom.TagName(sibling)) == -1 {
+					ps.trace(TraceEvent{Action: TraceConvert, Node: sibling, Tag: "div", Reason: "uncommon block element"})
 					ps.setNodeTag(sibling, "div")
 				}
 
@@ -1161,6 +1353,10 @@ func (ps *Parser) grabArticle() *html.Node {
 
 		// So we have all of the content that we need. Now we clean
 		// it up for presentation.
+		if err := ctx.Err(); err != nil {
+			ps.traceInfo(traceAttemptFinished)
+			return nil, err
+		}
 		ps.prepArticle(articleContent)
 
 		if neededToCreateTopCandidate {
@@ -1200,53 +1396,129 @@ func (ps *Parser) grabArticle() *html.Node {
 		// the sieve approach gives us a higher likelihood of
 		// finding the -right- content.
 		textLength := charCount(ps.getInnerText(articleContent, true))
+		attempt := parseAttempt{
+			articleContent:    articleContent,
+			textLength:        textLength,
+			topCandidateScore: topCandidateScore,
+			runnerUpScores:    runnerUpScores,
+			flags:             ps.flags,
+		}
+
 		if textLength < ps.CharThresholds {
 			parseSuccessful = false
 
 			if ps.flags.stripUnlikelys {
 				ps.flags.stripUnlikelys = false
-				ps.attempts = append(ps.attempts, parseAttempt{
-					articleContent: articleContent,
-					textLength:     textLength,
-				})
+				ps.attempts = append(ps.attempts, attempt)
 			} else if ps.flags.useWeightClasses {
 				ps.flags.useWeightClasses = false
-				ps.attempts = append(ps.attempts, parseAttempt{
-					articleContent: articleContent,
-					textLength:     textLength,
-				})
+				ps.attempts = append(ps.attempts, attempt)
 			} else if ps.flags.cleanConditionally {
 				ps.flags.cleanConditionally = false
-				ps.attempts = append(ps.attempts, parseAttempt{
-					articleContent: articleContent,
-					textLength:     textLength,
-				})
+				ps.attempts = append(ps.attempts, attempt)
 			} else {
-				ps.attempts = append(ps.attempts, parseAttempt{
-					articleContent: articleContent,
-					textLength:     textLength,
-				})
+				ps.attempts = append(ps.attempts, attempt)
+				attemptLengths := ps.getAttemptTextLengths()
 
 				// No luck after removing flags, just return the
 				// longest text we found during the different loops *
-				sort.Slice(ps.attempts, func(i, j int) bool {
+				sort.SliceStable(ps.attempts, func(i, j int) bool {
 					return ps.attempts[i].textLength > ps.attempts[j].textLength
 				})
 
 				// But first check if we actually have something
 				if ps.attempts[0].textLength == 0 {
-					return nil
+					ps.extraction = ps.getExtractionInfo(nil, parseAttempt{flags: ps.flags}, attemptLengths, false)
+					ps.traceInfo(traceAttemptFinished)
+					return nil, nil
 				}
 
 				articleContent = ps.attempts[0].articleContent
+				ps.extraction = ps.getExtractionInfo(articleContent, ps.attempts[0], attemptLengths, true)
 				parseSuccessful = true
 			}
+		} else {
+			attemptLengths := append(ps.getAttemptTextLengths(), textLength)
+			ps.extraction = ps.getExtractionInfo(articleContent, attempt, attemptLengths, false)
 		}
 
 		if parseSuccessful {
-			return articleContent
+			// Find out text direction from ancestors of final top candidate.
+			ancestors := append([]*html.Node{parentOfTopCandidate, topCandidate}, ps.getNodeAncestors(parentOfTopCandidate, 0)...)
+			ps.setArticleDir(ancestors)
+
+			ps.articleConfidence = ps.getConfidence(articleContent, len(ps.attempts))
+			ps.traceInfo(traceAttemptFinished)
+			return articleContent, nil
+		}
+
+		ps.traceInfo(traceAttemptFinished)
+	}
+}
+
+// setArticleDir uses the first dir attribute of the nodes as the article
+// direction.
+func (ps *Parser) setArticleDir(nodes []*html.Node) {
+	ps.someNode(nodes, func(node *html.Node) bool {
+		if node == nil || node.Type != html.ElementNode {
+			return false
 		}
+
+		if articleDir := dom.GetAttribute(node, "dir"); articleDir != "" {
+			ps.articleDir = articleDir
+			return true
+		}
+		return false
+	})
+}
+
+// getAttemptTextLengths returns the text length of the previous parse
+// attempts, in the order they were made.
+func (ps *Parser) getAttemptTextLengths() []int {
+	lengths := make([]int, len(ps.attempts))
+	for i, attempt := range ps.attempts {
+		lengths[i] = attempt.textLength
+	}
+	return lengths
+}
+
+// getExtractionInfo returns the diagnostic of the parse attempt that is
+// used as the article content.
+func (ps *Parser) getExtractionInfo(articleContent *html.Node, attempt parseAttempt, attemptLengths []int, usedFallback bool) ExtractionInfo {
+	info := ExtractionInfo{
+		TopCandidateScore:  attempt.topCandidateScore,
+		RunnerUpScores:     attempt.runnerUpScores,
+		StripUnlikelys:     attempt.flags.stripUnlikelys,
+		UseWeightClasses:   attempt.flags.useWeightClasses,
+		CleanConditionally: attempt.flags.cleanConditionally,
+		AttemptTextLengths: attemptLengths,
+		UsedFallback:       usedFallback,
 	}
+
+	if articleContent != nil {
+		info.LinkDensity = ps.getLinkDensity(articleContent)
+	}
+
+	return info
+}
+
+// getConfidence estimates how confident we are that articleContent is the
+// real article, as a number between 0 and 1. It's a heuristic based on
+// how long the text is compared to `CharThresholds`, how many links
+// the content has, and how many failed attempts it took to find it.
+func (ps *Parser) getConfidence(articleContent *html.Node, nFailedAttempts int) float64 {
+	textLength := charCount(ps.getInnerText(articleContent, true))
+	if textLength == 0 {
+		return 0
+	}
+
+	lengthRatio := 1.0
+	if ps.CharThresholds > 0 {
+		lengthRatio = math.Min(1, float64(textLength)/float64(ps.CharThresholds))
+	}
+
+	attemptRatio := math.Max(0, 1-0.15*float64(nFailedAttempts))
+	return lengthRatio * attemptRatio * (1 - ps.getLinkDensity(articleContent))
 }
 
 // isValidByline checks whether the input string could be a byline.
@@ -1272,44 +1544,17 @@ func (ps *Parser) getJSONLD() (map[string]string, error) {
 		// Strip CDATA markers if present
 		content := rxCDATA.ReplaceAllString(dom.TextContent(jsonLdElement), "")
 
-		// Decode JSON
-		var parsed map[string]interface{}
-		err := json.Unmarshal([]byte(content), &parsed)
+		// Decode JSON. The root might be an object or an array of objects.
+		var root interface{}
+		err := json.Unmarshal([]byte(content), &root)
 		if err != nil {
-			ps.logf("error while decoding json: %v", err)
-			return
-		}
-
-		// Check context
-		strContext, isString := parsed["@context"].(string)
-		if !isString || !rxSchemaOrg.MatchString(strContext) {
+			ps.traceInfo("error while decoding json: %v", err)
 			return
 		}
 
-		// If parsed doesn't have any @type, find it in its graph list
-		if _, typeExist := parsed["@type"]; !typeExist {
-			graphList, isArray := parsed["@graph"].([]interface{})
-			if !isArray {
-				return
-			}
-
-			for _, graph := range graphList {
-				objGraph, isObj := graph.(map[string]interface{})
-				if !isObj {
-					continue
-				}
-
-				strType, isString := objGraph["@type"].(string)
-				if isString && rxJsonLdArticleTypes.MatchString(strType) {
-					parsed = objGraph
-					break
-				}
-			}
-		}
-
-		// Once again, make sure parsed has valid @type
-		strType, isString := parsed["@type"].(string)
-		if !isString || !rxJsonLdArticleTypes.MatchString(strType) {
+		// Find the first object that describes an article
+		parsed := ps.findJSONLDArticle(root, false)
+		if parsed == nil {
 			return
 		}
 
@@ -1342,6 +1587,7 @@ func (ps *Parser) getJSONLD() (map[string]string, error) {
 		}
 
 		// Author
+		ps.jsonLdAuthors = ps.getJSONLDAuthors(parsed["author"])
 		switch val := parsed["author"].(type) {
 		case map[string]interface{}:
 			if name, isString := val["name"].(string); isString {
@@ -1375,21 +1621,206 @@ func (ps *Parser) getJSONLD() (map[string]string, error) {
 			}
 		}
 
-		// DatePublished
+		// DatePublished and DateModified
 		if datePublished, isString := parsed["datePublished"].(string); isString {
 			metadata["datePublished"] = datePublished
 		}
 
+		if dateModified, isString := parsed["dateModified"].(string); isString {
+			metadata["dateModified"] = dateModified
+		}
+
+		// Image
+		if image := jsonLDURL(parsed["image"], "url", "contentUrl"); image != "" {
+			metadata["image"] = image
+		}
+
+		// Keywords, might be a comma separated string or an array of string
+		if keywords := jsonLDStrings(parsed["keywords"]); len(keywords) > 0 {
+			metadata["keywords"] = strings.Join(keywords, ",")
+		}
+
+		// Section
+		if sections := jsonLDStrings(parsed["articleSection"]); len(sections) > 0 {
+			metadata["section"] = strings.Join(sections, ", ")
+		}
+
+		// Language, might be a language code or a Language object
+		switch val := parsed["inLanguage"].(type) {
+		case string:
+			metadata["language"] = strings.TrimSpace(val)
+		case map[string]interface{}:
+			if code, isString := val["alternateName"].(string); isString {
+				metadata["language"] = strings.TrimSpace(code)
+			}
+		}
+
+		// Word count, some sites put it as string
+		switch val := parsed["wordCount"].(type) {
+		case float64:
+			metadata["wordCount"] = strconv.Itoa(int(val))
+		case string:
+			if count, err := strconv.Atoi(strings.TrimSpace(val)); err == nil {
+				metadata["wordCount"] = strconv.Itoa(count)
+			}
+		}
+
+		// Paywall, either a boolean or "True" and "False" string
+		switch val := parsed["isAccessibleForFree"].(type) {
+		case bool:
+			metadata["isAccessibleForFree"] = strconv.FormatBool(val)
+		case string:
+			if free, err := strconv.ParseBool(strings.TrimSpace(val)); err == nil {
+				metadata["isAccessibleForFree"] = strconv.FormatBool(free)
+			}
+		}
+
+		// Main entity of page, either an URL or a WebPage object
+		if mainEntity := jsonLDURL(parsed["mainEntityOfPage"], "@id", "url"); mainEntity != "" {
+			metadata["mainEntityOfPage"] = mainEntity
+		}
 	})
 
 	return metadata, nil
 }
 
+// findJSONLDArticle looks for the first object within the decoded JSON-LD
+// value whose @type is one of the article types. The value might be an
+// array of objects, and the article might be nested in the @graph list.
+// Objects are only accepted if they (or their parent) use schema.org
+// as their context.
+func (ps *Parser) findJSONLDArticle(value interface{}, inSchemaOrg bool) map[string]interface{} {
+	switch val := value.(type) {
+	case []interface{}:
+		for _, item := range val {
+			if article := ps.findJSONLDArticle(item, inSchemaOrg); article != nil {
+				return article
+			}
+		}
+
+	case map[string]interface{}:
+		if _, hasContext := val["@context"]; hasContext {
+			inSchemaOrg = isSchemaOrgContext(val["@context"])
+		}
+
+		if !inSchemaOrg {
+			return nil
+		}
+
+		if isJSONLDArticleType(val["@type"]) {
+			return val
+		}
+
+		if graphList, isArray := val["@graph"].([]interface{}); isArray {
+			return ps.findJSONLDArticle(graphList, inSchemaOrg)
+		}
+	}
+
+	return nil
+}
+
+// isSchemaOrgContext checks if the JSON-LD @context refers to schema.org.
+// The context might be a string, an object with @vocab, or an array of both.
+func isSchemaOrgContext(context interface{}) bool {
+	switch val := context.(type) {
+	case string:
+		return rxSchemaOrg.MatchString(val)
+
+	case map[string]interface{}:
+		vocab, isString := val["@vocab"].(string)
+		return isString && rxSchemaOrg.MatchString(vocab)
+
+	case []interface{}:
+		for _, item := range val {
+			if isSchemaOrgContext(item) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// isJSONLDArticleType checks if the JSON-LD @type, which might be a string
+// or an array of string, contains one of the article types.
+func isJSONLDArticleType(jsonLDType interface{}) bool {
+	switch val := jsonLDType.(type) {
+	case string:
+		return rxJsonLdArticleTypes.MatchString(val)
+
+	case []interface{}:
+		for _, item := range val {
+			if strType, isString := item.(string); isString && rxJsonLdArticleTypes.MatchString(strType) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// jsonLDStrings returns the non-empty strings in a JSON-LD value that might
+// be a comma separated string or an array of string.
+func jsonLDStrings(value interface{}) []string {
+	var items []string
+	switch val := value.(type) {
+	case string:
+		items = strings.Split(val, ",")
+	case []interface{}:
+		for _, item := range val {
+			if strItem, isString := item.(string); isString {
+				items = append(items, strItem)
+			}
+		}
+	}
+
+	var result []string
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
+
+// jsonLDURL returns the URL in a JSON-LD value that might be a string,
+// an object that keeps the URL in one of the specified keys, or an array
+// of both. In case of array, the first URL will be used.
+func jsonLDURL(value interface{}, keys ...string) string {
+	switch val := value.(type) {
+	case string:
+		return strings.TrimSpace(val)
+
+	case map[string]interface{}:
+		for _, key := range keys {
+			if strURL, isString := val[key].(string); isString && strings.TrimSpace(strURL) != "" {
+				return strings.TrimSpace(strURL)
+			}
+		}
+
+	case []interface{}:
+		for _, item := range val {
+			if strURL := jsonLDURL(item, keys...); strURL != "" {
+				return strURL
+			}
+		}
+	}
+
+	return ""
+}
+
 // getArticleMetadata attempts to get excerpt and byline
-// metadata for the article.
-func (ps *Parser) getArticleMetadata(jsonLd map[string]string) map[string]string {
+// metadata for the article. For each field, JSON-LD is used first,
+// followed by the meta tags (e.g. Dublin Core and OpenGraph), then by
+// microdata or RDFa. Microdata is used last since it's spread in the
+// body, so it's less reliable than the metadata in the head. The
+// exception is image, where the meta tags are preferred over JSON-LD
+// since they are commonly used to pick the thumbnail.
+func (ps *Parser) getArticleMetadata(jsonLd, microdata map[string]string) map[string]string {
 	values := make(map[string]string)
 	metaElements := dom.GetElementsByTagName(ps.doc, "meta")
+	var ogImages []string
 
 	// Find description tags.
 	ps.forEachNode(metaElements, func(element *html.Node, _ int) {
@@ -1411,6 +1842,9 @@ func (ps *Parser) getArticleMetadata(jsonLd map[string]string) map[string]string
 				name = strings.Join(strings.Fields(name), "")
 				// multiple authors
 				values[name] = strings.TrimSpace(content)
+				if name == "og:image" {
+					ogImages = append(ogImages, strings.TrimSpace(content))
+				}
 			}
 		}
 
@@ -1433,7 +1867,8 @@ func (ps *Parser) getArticleMetadata(jsonLd map[string]string) map[string]string
 		values["weibo:article:title"],
 		values["weibo:webpage:title"],
 		values["title"],
-		values["twitter:title"])
+		values["twitter:title"],
+		microdata["title"])
 
 	if metadataTitle == "" {
 		metadataTitle = ps.getArticleTitle()
@@ -1444,7 +1879,8 @@ func (ps *Parser) getArticleMetadata(jsonLd map[string]string) map[string]string
 		jsonLd["byline"],
 		values["dc:creator"],
 		values["dcterm:creator"],
-		values["author"])
+		values["author"],
+		microdata["byline"])
 
 	// get description
 	metadataExcerpt := strOr(
@@ -1458,17 +1894,46 @@ func (ps *Parser) getArticleMetadata(jsonLd map[string]string) map[string]string
 		values["twitter:description"])
 
 	// get site name
-	metadataSiteName := strOr(jsonLd["siteName"], values["og:site_name"])
-
-	// get image thumbnail
-	metadataImage := strOr(
-		values["og:image"],
+	metadataSiteName := strOr(
+		jsonLd["siteName"],
+		values["og:site_name"],
+		microdata["siteName"])
+
+	// get image thumbnail, using the first valid one. The last og:image
+	// is preferred, but the earlier ones are used if it's invalid.
+	var imageCandidates []string
+	for i := len(ogImages) - 1; i >= 0; i-- {
+		imageCandidates = append(imageCandidates, ogImages[i])
+	}
+	imageCandidates = append(imageCandidates,
 		values["image"],
-		values["twitter:image"])
+		values["twitter:image"],
+		jsonLd["image"],
+		microdata["image"])
+
+	var metadataImage string
+	for _, image := range imageCandidates {
+		if metadataImage = ps.resolveMetadataURI(image); metadataImage != "" {
+			break
+		}
+	}
+
+	if ps.PreferLargestImage {
+		if largestImage := ps.getLargestImage(metaElements); largestImage != "" {
+			metadataImage = largestImage
+		}
+	}
 
 	// get favicon
 	metadataFavicon := ps.getArticleFavicon()
 
+	// get canonical URL
+	metadataCanonicalURL := values["og:url"]
+	for _, link := range dom.QuerySelectorAll(ps.doc, `link[rel~="canonical"][href]`) {
+		metadataCanonicalURL = dom.GetAttribute(link, "href")
+		break
+	}
+
 	// get published date
 	metadataPublishedTime := strOr(
 		jsonLd["datePublished"],
@@ -1477,6 +1942,7 @@ func (ps *Parser) getArticleMetadata(jsonLd map[string]string) map[string]string
 		values["dcterms.created"],
 		values["dcterms.issued"],
 		values["weibo:article:create_at"],
+		microdata["datePublished"],
 	)
 
 	// get modified date
@@ -1484,8 +1950,16 @@ func (ps *Parser) getArticleMetadata(jsonLd map[string]string) map[string]string
 		jsonLd["dateModified"],
 		values["article:modified_time"],
 		values["dcterms.modified"],
+		microdata["dateModified"],
 	)
 
+	// get keywords and section
+	metadataKeywords := strOr(jsonLd["keywords"], values["keywords"])
+	metadataSection := strOr(jsonLd["section"], values["article:section"])
+
+	// get main entity of page
+	metadataMainEntity := jsonLd["mainEntityOfPage"]
+
 	// in many sites the meta value is escaped with HTML entities,
 	// so here we need to unescape it
 	metadataTitle = shtml.UnescapeString(metadataTitle)
@@ -1494,16 +1968,30 @@ func (ps *Parser) getArticleMetadata(jsonLd map[string]string) map[string]string
 	metadataSiteName = shtml.UnescapeString(metadataSiteName)
 	metadataPublishedTime = shtml.UnescapeString(metadataPublishedTime)
 	metadataModifiedTime = shtml.UnescapeString(metadataModifiedTime)
+	metadataKeywords = shtml.UnescapeString(metadataKeywords)
+	metadataSection = shtml.UnescapeString(metadataSection)
+
+	// URLs might be relative, so convert them to absolute URLs
+	metadataImage = ps.resolveMetadataURI(metadataImage)
+	metadataCanonicalURL = ps.resolveMetadataURI(metadataCanonicalURL)
+	metadataMainEntity = ps.resolveMetadataURI(metadataMainEntity)
 
 	return map[string]string{
-		"title":         metadataTitle,
-		"byline":        metadataByline,
-		"excerpt":       metadataExcerpt,
-		"siteName":      metadataSiteName,
-		"image":         metadataImage,
-		"favicon":       metadataFavicon,
-		"publishedTime": metadataPublishedTime,
-		"modifiedTime":  metadataModifiedTime,
+		"title":               metadataTitle,
+		"byline":              metadataByline,
+		"excerpt":             metadataExcerpt,
+		"siteName":            metadataSiteName,
+		"image":               metadataImage,
+		"favicon":             metadataFavicon,
+		"canonicalURL":        metadataCanonicalURL,
+		"publishedTime":       metadataPublishedTime,
+		"modifiedTime":        metadataModifiedTime,
+		"keywords":            metadataKeywords,
+		"section":             metadataSection,
+		"language":            jsonLd["language"],
+		"wordCount":           jsonLd["wordCount"],
+		"isAccessibleForFree": jsonLd["isAccessibleForFree"],
+		"mainEntityOfPage":    metadataMainEntity,
 	}
 }
 
@@ -1544,6 +2032,7 @@ func (ps *Parser) unwrapNoscriptImages(doc *html.Node) {
 			}
 		}
 
+		ps.trace(TraceEvent{Action: TraceRemove, Node: img, Reason: "placeholder image"})
 		img.Parent.RemoveChild(img)
 	})
 
@@ -1597,6 +2086,7 @@ func (ps *Parser) unwrapNoscriptImages(doc *html.Node) {
 				}
 			}
 
+			ps.trace(TraceEvent{Action: TraceConvert, Node: prevElement, Tag: "img", Reason: "image replaced by its noscript version"})
 			dom.ReplaceChild(noscript.Parent, dom.FirstElementChild(tmpBody), prevElement)
 		}
 	})
@@ -1604,7 +2094,7 @@ func (ps *Parser) unwrapNoscriptImages(doc *html.Node) {
 
 // removeScripts removes script tags from the document.
 func (ps *Parser) removeScripts(doc *html.Node) {
-	ps.removeNodes(ps.getAllNodesWithTag(doc, "script", "noscript"), nil)
+	ps.removeNodes(ps.getAllNodesWithTag(doc, "script", "noscript"), "script element", nil)
 }
 
 // hasSingleTagInsideElement check if this node has only whitespace
@@ -1727,7 +2217,13 @@ func (ps *Parser) getLinkDensity(element *html.Node) float64 {
 // getClassWeight gets an elements class/id weight. Uses regular
 // expressions to tell if this element looks good or bad.
 func (ps *Parser) getClassWeight(node *html.Node) int {
-	if !ps.flags.useWeightClasses {
+	return ps.getClassWeightWithFlags(node, ps.flags)
+}
+
+// getClassWeightWithFlags is like getClassWeight, but uses the specified
+// flags instead of the flags of the current parse attempt.
+func (ps *Parser) getClassWeightWithFlags(node *html.Node, f flags) int {
+	if !f.useWeightClasses {
 		return 0
 	}
 
@@ -1735,22 +2231,22 @@ func (ps *Parser) getClassWeight(node *html.Node) int {
 
 	// Look for a special classname
 	if nodeClassName := dom.ClassName(node); nodeClassName != "" {
-		if re2go.IsNegativeClass(nodeClassName) {
+		if ps.isNegativeClass(nodeClassName) {
 			weight -= 25
 		}
 
-		if re2go.IsPositiveClass(nodeClassName) {
+		if ps.isPositiveClass(nodeClassName) {
 			weight += 25
 		}
 	}
 
 	// Look for a special ID
 	if nodeID := dom.ID(node); nodeID != "" {
-		if re2go.IsNegativeClass(nodeID) {
+		if ps.isNegativeClass(nodeID) {
 			weight -= 25
 		}
 
-		if re2go.IsPositiveClass(nodeID) {
+		if ps.isPositiveClass(nodeID) {
 			weight += 25
 		}
 	}
@@ -1767,7 +2263,7 @@ func (ps *Parser) clean(node *html.Node, tag string) {
 		rxVideoVilter = rxVideos
 	}
 
-	ps.removeNodes(dom.GetElementsByTagName(node, tag), func(element *html.Node) bool {
+	ps.removeNodes(dom.GetElementsByTagName(node, tag), "unwanted "+tag+" element", func(element *html.Node) bool {
 		// Allow youtube and vimeo videos through as people usually want to see those.
 		if isEmbed {
 			// First, check the elements attributes to see if any of them contain
@@ -1844,66 +2340,54 @@ func (ps *Parser) getRowAndColumnCount(table *html.Node) (int, int) {
 // and mark it, which similar as used in Firefox:
 // https://searchfox.org/mozilla-central/rev/f82d5c549f046cb64ce5602bfd894b7ae807c8f8/accessible/generic/TableAccessible.cpp#19
 func (ps *Parser) markDataTables(root *html.Node) {
-	tables := dom.GetElementsByTagName(root, "table")
-	for i := 0; i < len(tables); i++ {
-		table := tables[i]
-
-		role := dom.GetAttribute(table, "role")
-		if role == "presentation" {
-			ps.setReadabilityDataTable(table, false)
-			continue
-		}
-
-		datatable := dom.GetAttribute(table, "datatable")
-		if datatable == "0" {
-			ps.setReadabilityDataTable(table, false)
-			continue
-		}
+	for _, table := range dom.GetElementsByTagName(root, "table") {
+		ps.setReadabilityDataTable(table, ps.isDataTable(table))
+	}
+}
 
-		if dom.HasAttribute(table, "summary") {
-			ps.setReadabilityDataTable(table, true)
-			continue
-		}
+// isDataTable checks whether the table is used to show data, instead of
+// only for layout.
+func (ps *Parser) isDataTable(table *html.Node) bool {
+	role := dom.GetAttribute(table, "role")
+	if role == "presentation" {
+		return false
+	}
 
-		if captions := dom.GetElementsByTagName(table, "caption"); len(captions) > 0 {
-			if caption := captions[0]; caption != nil && len(dom.ChildNodes(caption)) > 0 {
-				ps.setReadabilityDataTable(table, true)
-				continue
-			}
-		}
+	datatable := dom.GetAttribute(table, "datatable")
+	if datatable == "0" {
+		return false
+	}
 
-		// If the table has a descendant with any of these tags, consider a data table:
-		hasDataTableDescendantTags := false
-		for _, descendantTag := range []string{"col", "colgroup", "tfoot", "thead", "th"} {
-			descendants := dom.GetElementsByTagName(table, descendantTag)
-			if len(descendants) > 0 && descendants[0] != nil {
-				hasDataTableDescendantTags = true
-				break
-			}
-		}
+	if dom.HasAttribute(table, "summary") {
+		return true
+	}
 
-		if hasDataTableDescendantTags {
-			ps.setReadabilityDataTable(table, true)
-			continue
+	if captions := dom.GetElementsByTagName(table, "caption"); len(captions) > 0 {
+		if caption := captions[0]; caption != nil && len(dom.ChildNodes(caption)) > 0 {
+			return true
 		}
+	}
 
-		// Nested tables indicates a layout table:
-		if len(dom.GetElementsByTagName(table, "table")) > 0 {
-			ps.setReadabilityDataTable(table, false)
-			continue
+	// If the table has a descendant with any of these tags, consider a data table:
+	for _, descendantTag := range []string{"col", "colgroup", "tfoot", "thead", "th"} {
+		descendants := dom.GetElementsByTagName(table, descendantTag)
+		if len(descendants) > 0 && descendants[0] != nil {
+			return true
 		}
+	}
 
-		rows, columns := ps.getRowAndColumnCount(table)
-		if rows >= 10 || columns > 4 {
-			ps.setReadabilityDataTable(table, true)
-			continue
-		}
+	// Nested tables indicates a layout table:
+	if len(dom.GetElementsByTagName(table, "table")) > 0 {
+		return false
+	}
 
-		// Now just go by size entirely:
-		if rows*columns > 10 {
-			ps.setReadabilityDataTable(table, true)
-		}
+	rows, columns := ps.getRowAndColumnCount(table)
+	if rows >= 10 || columns > 4 {
+		return true
 	}
+
+	// Now just go by size entirely:
+	return rows*columns > 10
 }
 
 // fixLazyImages convert images and figures that have properties like data-src into
@@ -2008,7 +2492,7 @@ func (ps *Parser) cleanConditionally(element *html.Node, tag string) {
 	// Traverse backwards so we can remove nodes at the same time
 	// without effecting the traversal.
 	// TODO: Consider taking into account original contentScore here.
-	ps.removeNodes(dom.GetElementsByTagName(element, tag), func(node *html.Node) bool {
+	ps.removeNodes(dom.GetElementsByTagName(element, tag), "", func(node *html.Node) bool {
 		// First check if this node IS data table, in which case don't remove it.
 		if tag == "table" && ps.isReadabilityDataTable(node) {
 			return false
@@ -2038,6 +2522,7 @@ func (ps *Parser) cleanConditionally(element *html.Node, tag string) {
 		var contentScore int
 		weight := ps.getClassWeight(node)
 		if weight+contentScore < 0 {
+			ps.trace(TraceEvent{Action: TraceRemove, Node: node, Reason: "negative class weight"})
 			return true
 		}
 
@@ -2073,30 +2558,44 @@ func (ps *Parser) cleanConditionally(element *html.Node, tag string) {
 
 			linkDensity := ps.getLinkDensity(node)
 			contentLength := charCount(ps.getInnerText(node, true))
-			haveToRemove := (img > 1 && p/img < 0.5 && !ps.hasAncestorTag(node, "figure", 3, nil)) ||
-				(!isList && li > p) ||
-				(input > math.Floor(p/3)) ||
-				(!isList && headingDensity < 0.9 && contentLength < 25 && (img == 0 || img > 2) && !ps.hasAncestorTag(node, "figure", 3, nil)) ||
-				(!isList && weight < 25 && linkDensity > 0.2) ||
-				(weight >= 25 && linkDensity > 0.5) ||
-				((embedCount == 1 && contentLength < 75) || embedCount > 1)
+			// The reasons are checked in the same order as the
+			// conditions in Readability.js, so the first match wins.
+			var removeReason string
+			switch {
+			case img > 1 && p/img < 0.5 && !ps.hasAncestorTag(node, "figure", 3, nil):
+				removeReason = "more images than paragraphs"
+			case !isList && li > p:
+				removeReason = "more list items than paragraphs"
+			case input > math.Floor(p/3):
+				removeReason = "too many inputs"
+			case !isList && headingDensity < 0.9 && contentLength < 25 && (img == 0 || img > 2) && !ps.hasAncestorTag(node, "figure", 3, nil):
+				removeReason = "content too short"
+			case !isList && weight < 25 && linkDensity > 0.2:
+				removeReason = "high link density"
+			case weight >= 25 && linkDensity > 0.5:
+				removeReason = "high link density despite good class weight"
+			case (embedCount == 1 && contentLength < 75) || embedCount > 1:
+				removeReason = "too many embeds"
+			}
+			haveToRemove := removeReason != ""
 
 			// Allow simple lists of images to remain in pages
 			if isList && haveToRemove {
-				for _, child := range dom.Children(node) {
-					// Don't filter in lists with li's that contain more than one child
-					if len(dom.Children(child)) > 1 {
-						return haveToRemove
-					}
-				}
+				// Only allow the list to remain if every li contains an image.
+				// Don't filter in lists with li's that contain more than one child
+				hasComplexItem := ps.someNode(dom.Children(node), func(child *html.Node) bool {
+					return len(dom.Children(child)) > 1
+				})
 
-				// Only allow the list to remain if every li contains an image
 				liCount := len(dom.GetElementsByTagName(node, "li"))
-				if int(img) == liCount {
+				if !hasComplexItem && int(img) == liCount {
 					return false
 				}
 			}
 
+			if haveToRemove {
+				ps.trace(TraceEvent{Action: TraceRemove, Node: node, Reason: removeReason})
+			}
 			return haveToRemove
 		}
 
@@ -2121,13 +2620,9 @@ func (ps *Parser) cleanMatchedNodes(e *html.Node, filter func(*html.Node, string
 // cleanHeaders cleans out spurious headers from an Element.
 func (ps *Parser) cleanHeaders(e *html.Node) {
 	headingNodes := ps.getAllNodesWithTag(e, "h1", "h2")
-	ps.removeNodes(headingNodes, func(node *html.Node) bool {
+	ps.removeNodes(headingNodes, "header with low class weight", func(node *html.Node) bool {
 		// Removing header with low class weight
-		if ps.getClassWeight(node) < 0 {
-			ps.logf("removing header with low class weight: %q\n", dom.OuterHTML(node))
-			return true
-		}
-		return false
+		return ps.getClassWeight(node) < 0
 	})
 }
 
@@ -2139,7 +2634,6 @@ func (ps *Parser) headerDuplicatesTitle(node *html.Node) bool {
 	}
 
 	heading := ps.getInnerText(node, false)
-	ps.logf("evaluating similarity of header: %q and %q\n", heading, ps.articleTitle)
 	return ps.textSimilarity(ps.articleTitle, heading) > 0.75
 }
 
@@ -2205,7 +2699,136 @@ func (ps *Parser) getArticleFavicon() string {
 		}
 	})
 
-	return toAbsoluteURI(favicon, ps.documentURI)
+	return ps.resolveMetadataURI(favicon)
+}
+
+// getLargestImage returns the og:image with the largest size, as declared
+// by og:image:width and og:image:height. Returns empty string if none of
+// the images declares its size.
+func (ps *Parser) getLargestImage(metaElements []*html.Node) string {
+	type imageCandidate struct {
+		url    string
+		width  int
+		height int
+	}
+
+	var images []*imageCandidate
+	var current *imageCandidate
+	ps.forEachNode(metaElements, func(element *html.Node, _ int) {
+		property := strings.ToLower(strings.TrimSpace(dom.GetAttribute(element, "property")))
+		content := strings.TrimSpace(dom.GetAttribute(element, "content"))
+		if content == "" {
+			return
+		}
+
+		switch property {
+		case "og:image", "og:image:url":
+			// og:image:url is an alias of og:image, so if it's put right
+			// after og:image, both of them refer to the same image.
+			if property == "og:image:url" && current != nil && current.width == 0 && current.height == 0 {
+				return
+			}
+
+			// Invalid image is ignored, along with its size
+			if ps.resolveMetadataURI(content) == "" {
+				current = nil
+				return
+			}
+
+			current = &imageCandidate{url: content}
+			images = append(images, current)
+		case "og:image:secure_url":
+			// The secure URL is the same image, so it's preferred as
+			// long as it's valid.
+			if current != nil && ps.resolveMetadataURI(content) != "" {
+				current.url = content
+			}
+		case "og:image:width":
+			if current != nil {
+				current.width, _ = strconv.Atoi(content)
+			}
+		case "og:image:height":
+			if current != nil {
+				current.height, _ = strconv.Atoi(content)
+			}
+		}
+	})
+
+	largestImage := ""
+	largestSize := 0
+	for _, image := range images {
+		size := image.width
+		if image.height > 0 {
+			size *= image.height
+		}
+
+		if size > largestSize {
+			largestImage = image.url
+			largestSize = size
+		}
+	}
+
+	return largestImage
+}
+
+// resolveMetadataURI converts uri found in the page metadata into absolute
+// URI. Returns empty string if uri is invalid or uses javascript: scheme.
+func (ps *Parser) resolveMetadataURI(uri string) string {
+	uri = strings.TrimSpace(uri)
+	if uri == "" || strings.HasPrefix(strings.ToLower(uri), "javascript:") {
+		return ""
+	}
+
+	if _, err := nurl.Parse(uri); err != nil {
+		return ""
+	}
+
+	return ps.resolveURI(uri)
+}
+
+// findBaseURI returns the URI that used to resolve relative URIs in the
+// document. It's the href of the first <base> element, resolved against
+// the document URI. If there are no valid <base>, returns the document URI.
+func (ps *Parser) findBaseURI() *nurl.URL {
+	for _, base := range dom.GetElementsByTagName(ps.doc, "base") {
+		href := strings.TrimSpace(dom.GetAttribute(base, "href"))
+		if href == "" {
+			continue
+		}
+
+		// Only the first <base> with href is used, even if it's invalid.
+		baseURI, err := nurl.Parse(href)
+		if err != nil {
+			break
+		}
+
+		if ps.documentURI != nil {
+			baseURI = ps.documentURI.ResolveReference(baseURI)
+		}
+
+		if !baseURI.IsAbs() || baseURI.Scheme == "data" || baseURI.Scheme == "javascript" {
+			break
+		}
+
+		return baseURI
+	}
+
+	return ps.documentURI
+}
+
+// resolveURI converts uri to absolute URI based on the base URI of the
+// document. Just like in Readability.js, hash links are only left alone
+// when the base URI is the same as the document URI, since otherwise they
+// point to a different page.
+func (ps *Parser) resolveURI(uri string) string {
+	if strings.HasPrefix(uri, "#") && ps.baseURI != nil && ps.documentURI != nil &&
+		ps.baseURI.String() != ps.documentURI.String() {
+		if tmp, err := nurl.Parse(uri); err == nil {
+			return ps.baseURI.ResolveReference(tmp).String()
+		}
+	}
+
+	return toAbsoluteURI(uri, ps.baseURI)
 }
 
 // removeComments find all comments in document then remove it.
@@ -2229,7 +2852,7 @@ func (ps *Parser) removeComments(doc *html.Node) {
 	}
 
 	// Remove it
-	ps.removeNodes(comments, nil)
+	ps.removeNodes(comments, "comment", nil)
 }
 
 // In dynamic language like JavaScript, we can easily add new
@@ -2286,33 +2909,19 @@ func (ps *Parser) getContentScore(node *html.Node) float64 {
 func (ps *Parser) clearReadabilityAttr(node *html.Node) {
 	dom.RemoveAttribute(node, "data-readability-score")
 	dom.RemoveAttribute(node, "data-readability-table")
+	dom.RemoveAttribute(node, keepAttr)
 
 	for child := dom.FirstElementChild(node); child != nil; child = dom.NextElementSibling(child) {
 		ps.clearReadabilityAttr(child)
 	}
 }
 
-func (ps *Parser) log(args ...interface{}) {
-	if ps.Debug {
-		log.Println(args...)
-	}
-}
-
-func (ps *Parser) logf(format string, args ...interface{}) {
-	if ps.Debug {
-		log.Printf(format, args...)
-	}
-}
-
 // UNUSED CODES
 // Codes below these points are defined in original Readability.js but not used,
 // so here we commented it out so it can be used later if necessary.
 
 // var (
-// 	rxExtraneous   = regexp.MustCompile(`(?i)print|archive|comment|discuss|e[\-]?mail|share|reply|all|login|sign|single|utility`)
 // 	rxReplaceFonts = regexp.MustCompile(`(?i)<(/?)font[^>]*>`)
-// 	rxNextLink     = regexp.MustCompile(`(?i)(next|weiter|continue|>([^\|]|$)|»([^\|]|$))`)
-// 	rxPrevLink     = regexp.MustCompile(`(?i)(prev|earl|old|new|<|«)`)
 // )
 
 // // findNode iterates over a NodeList and return the first node that passes
@@ -1,7 +1,9 @@
 package readability
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
 	"os"
@@ -19,11 +21,18 @@ var (
 	fakeHostURL, _ = url.ParseRequestURI("http://fakehost/test/page.html")
 )
 
+// loremParagraph returns a paragraph that repeats a lorem ipsum sentence n
+// times. With 20 sentences it's long enough to be an article by itself.
+func loremParagraph(n int) string {
+	return "<p>" + strings.Repeat("Lorem ipsum dolor sit amet, consectetur adipiscing elit. ", n) + "</p>"
+}
+
 type ExpectedMetadata struct {
 	Title         string `json:"title,omitempty"`
 	Byline        string `json:"byline,omitempty"`
 	Excerpt       string `json:"excerpt,omitempty"`
 	Language      string `json:"language,omitempty"`
+	Dir           string `json:"dir,omitempty"`
 	SiteName      string `json:"siteName,omitempty"`
 	Readerable    bool   `json:"readerable"`
 	PublishedTime string `json:"publishedTime,omitempty"`
@@ -98,6 +107,10 @@ func Test_parser(t *testing.T) {
 				t1.Errorf("language, want %q got %q\n", metadata.Language, article.Language)
 			}
 
+			if metadata.Dir != article.Dir {
+				t1.Errorf("dir, want %q got %q\n", metadata.Dir, article.Dir)
+			}
+
 			if !timesAreEqual(metadata.PublishedTime, article.PublishedTime) {
 				t1.Errorf("date published, want %q got %q\n", metadata.PublishedTime, article.PublishedTime)
 			}
@@ -110,6 +123,261 @@ func Test_parser(t *testing.T) {
 	}
 }
 
+func Test_parserContext(t *testing.T) {
+	f, err := os.Open(fp.Join("test-pages", "wikipedia", "source.html"))
+	if err != nil {
+		t.Fatalf("failed to open source: %v", err)
+	}
+	defer f.Close()
+
+	doc, err := dom.Parse(f)
+	if err != nil {
+		t.Fatalf("failed to decode source: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	parser := NewParser()
+	if _, err := parser.ParseDocumentContext(ctx, doc, fakeHostURL); !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled context, want %v got %v", context.Canceled, err)
+	}
+
+	article, err := parser.ParseDocumentContext(context.Background(), doc, fakeHostURL)
+	if err != nil || article.Node == nil {
+		t.Errorf("background context, want article got %v", err)
+	}
+}
+
+func Test_articleFound(t *testing.T) {
+	articleSource, err := os.ReadFile(fp.Join("test-pages", "bbc-1", "source.html"))
+	if err != nil {
+		t.Fatalf("failed to read source: %v", err)
+	}
+
+	scenarios := map[string]struct {
+		source        string
+		found         bool
+		minConfidence float64
+		maxConfidence float64
+	}{
+		"empty page": {"<html><body></body></html>", false, 0, 0},
+		"bbc-1":      {string(articleSource), true, 0.9, 1},
+		"short text": {"<html><body><article><p>Short text</p></article></body></html>", true, 0, 0.1},
+	}
+
+	for name, expected := range scenarios {
+		article, err := FromReader(strings.NewReader(expected.source), fakeHostURL)
+		if err != nil {
+			t.Fatalf("failed to parse %s: %v", name, err)
+		}
+
+		if article.Found != expected.found ||
+			article.Confidence < expected.minConfidence ||
+			article.Confidence > expected.maxConfidence {
+			t.Errorf("\n"+
+				"page : %s\n"+
+				"want : found %v, confidence %.2f-%.2f\n"+
+				"got  : found %v, confidence %.2f", name, expected.found,
+				expected.minConfidence, expected.maxConfidence, article.Found, article.Confidence)
+		}
+	}
+}
+
+func Test_languageWithoutContent(t *testing.T) {
+	source := `<html lang="fr"><body></body></html>`
+	article, err := FromReader(strings.NewReader(source), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if article.Found || article.Language != "fr" {
+		t.Errorf("page without content, want language %q got %q (found %v)", "fr", article.Language, article.Found)
+	}
+}
+
+func Test_extractionInfo(t *testing.T) {
+	parseTestPage := func(name string) ExtractionInfo {
+		f, err := os.Open(fp.Join("test-pages", name, "source.html"))
+		if err != nil {
+			t.Fatalf("failed to open source: %v", err)
+		}
+		defer f.Close()
+
+		article, err := FromReader(f, fakeHostURL)
+		if err != nil {
+			t.Fatalf("failed to parse %s: %v", name, err)
+		}
+		return article.Extraction
+	}
+
+	info := parseTestPage("bbc-1")
+	if info.UsedFallback || len(info.AttemptTextLengths) != 1 ||
+		!info.StripUnlikelys || !info.UseWeightClasses || !info.CleanConditionally {
+		t.Errorf("bbc-1, want one attempt with every flag enabled, got %+v", info)
+	}
+
+	if len(info.RunnerUpScores) == 0 || info.TopCandidateScore <= info.RunnerUpScores[0] {
+		t.Errorf("bbc-1, want top candidate scored above the runner-ups, got %+v", info)
+	}
+
+	// The article is only found once the unlikely candidates are kept
+	info = parseTestPage("lifehacker-working")
+	if info.UsedFallback || len(info.AttemptTextLengths) != 2 || info.AttemptTextLengths[0] != 0 ||
+		info.StripUnlikelys || !info.UseWeightClasses || !info.CleanConditionally {
+		t.Errorf("lifehacker-working, want the second attempt without stripUnlikelys, got %+v", info)
+	}
+
+	article, err := FromReader(strings.NewReader("<html><body><article><p>Short text</p></article></body></html>"), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	info = article.Extraction
+	if !info.UsedFallback || len(info.AttemptTextLengths) != 4 ||
+		info.StripUnlikelys || info.UseWeightClasses || info.CleanConditionally {
+		t.Errorf("short article, want fallback after four attempts, got %+v", info)
+	}
+
+	// The unlikely candidate is only kept once stripUnlikelys is disabled,
+	// so the fallback must report the flags of that longer attempt.
+	source := "<html><body><p>Short text</p><div class=\"sidebar\"><p>" +
+		strings.Repeat("Sidebar text. ", 10) + "</p></div></body></html>"
+	article, err = FromReader(strings.NewReader(source), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	info = article.Extraction
+	if !info.UsedFallback || len(info.AttemptTextLengths) != 4 ||
+		info.StripUnlikelys || !info.UseWeightClasses || !info.CleanConditionally {
+		t.Errorf("unlikely article, want fallback to the second attempt flags, got %+v", info)
+	}
+}
+
+func Test_metadataURLs(t *testing.T) {
+	source := `<html><head>
+		<base href="/base/">
+		<link rel="canonical" href="../canonical/page.html">
+		<link rel="icon" type="image/png" sizes="32x32" href="//cdn.fakehost/icon-32.png">
+		<meta property="og:image" content="small.jpg">
+		<meta property="og:image:width" content="200">
+		<meta property="og:image" content="large.jpg">
+		<meta property="og:image:secure_url" content="https://secure.fakehost/large.jpg">
+		<meta property="og:image:width" content="1200">
+		<meta property="og:image" content="medium.jpg">
+		<meta property="og:image:width" content="600">
+		<meta property="og:image" content="javascript:alert(1)">
+		</head><body><p>Hello</p></body></html>`
+
+	scenarios := map[bool]Article{
+		false: {Image: "http://fakehost/base/medium.jpg", Favicon: "http://cdn.fakehost/icon-32.png", CanonicalURL: "http://fakehost/canonical/page.html"},
+		true:  {Image: "https://secure.fakehost/large.jpg", Favicon: "http://cdn.fakehost/icon-32.png", CanonicalURL: "http://fakehost/canonical/page.html"},
+	}
+
+	for preferLargestImage, expected := range scenarios {
+		parser := NewParser()
+		parser.PreferLargestImage = preferLargestImage
+
+		article, err := parser.Parse(strings.NewReader(source), fakeHostURL)
+		if err != nil {
+			t.Fatalf("failed to parse: %v", err)
+		}
+
+		if article.Image != expected.Image {
+			t.Errorf("image (largest %v), want %q got %q", preferLargestImage, expected.Image, article.Image)
+		}
+
+		if article.Favicon != expected.Favicon {
+			t.Errorf("favicon, want %q got %q", expected.Favicon, article.Favicon)
+		}
+
+		if article.CanonicalURL != expected.CanonicalURL {
+			t.Errorf("canonical URL, want %q got %q", expected.CanonicalURL, article.CanonicalURL)
+		}
+	}
+
+	// Invalid og:image falls back to twitter:image
+	source = `<html><head>
+		<meta property="og:image" content="javascript:alert(1)">
+		<meta name="twitter:image" content="/twitter.jpg">
+		</head><body><p>Hello</p></body></html>`
+
+	parser := NewParser()
+	article, err := parser.Parse(strings.NewReader(source), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if article.Image != "http://fakehost/twitter.jpg" {
+		t.Errorf("image, want %q got %q", "http://fakehost/twitter.jpg", article.Image)
+	}
+}
+
+func Test_getJSONLD(t *testing.T) {
+	scenarios := map[string]map[string]string{
+		// @type as array
+		`{"@context":"https://schema.org","@type":["NewsArticle","Article"],"headline":"Title"}`: {
+			"title": "Title",
+		},
+		// @context as object and article within @graph
+		`{"@context":{"@vocab":"http://schema.org/"},"@graph":[{"@type":"WebPage"},{"@type":"BlogPosting","name":"Title"}]}`: {
+			"title": "Title",
+		},
+		// Top level array, the first object is not an article
+		`[{"@context":"https://schema.org","@type":"Organization","name":"Org"},{"@context":"https://schema.org","@type":"Article","name":"Title"}]`: {
+			"title": "Title",
+		},
+		// Other context is rejected
+		`{"@context":"https://example.com","@type":"Article","name":"Title"}`: nil,
+		// All fields
+		`{"@context":"https://schema.org","@type":"Article","name":"Title",
+		"dateModified":"2020-01-02","image":[{"@type":"ImageObject","url":"/a.jpg"}],
+		"keywords":["Go"," Readability "],"articleSection":"Tech","inLanguage":{"alternateName":"en"},
+		"wordCount":"120","isAccessibleForFree":"False","mainEntityOfPage":{"@id":"http://fakehost/page"}}`: {
+			"title":               "Title",
+			"dateModified":        "2020-01-02",
+			"image":               "/a.jpg",
+			"keywords":            "Go,Readability",
+			"section":             "Tech",
+			"language":            "en",
+			"wordCount":           "120",
+			"isAccessibleForFree": "false",
+			"mainEntityOfPage":    "http://fakehost/page",
+		},
+	}
+
+	for source, expected := range scenarios {
+		doc, err := dom.Parse(strings.NewReader(`<html><head><script type="application/ld+json">` +
+			source + `</script></head><body></body></html>`))
+		if err != nil {
+			t.Fatalf("failed to parse %q: %v", source, err)
+		}
+
+		parser := NewParser()
+		parser.doc = doc
+
+		metadata, _ := parser.getJSONLD()
+		if len(metadata) != len(expected) {
+			t.Errorf("\n"+
+				"source : %s\n"+
+				"want   : %v\n"+
+				"got    : %v", source, expected, metadata)
+			continue
+		}
+
+		for key, value := range expected {
+			if metadata[key] != value {
+				t.Errorf("\n"+
+					"source : %s\n"+
+					"key    : %s\n"+
+					"want   : %q\n"+
+					"got    : %q", source, key, value, metadata[key])
+			}
+		}
+	}
+}
+
 func extractSourceFile(path string) (Article, *html.Node, *html.Node, error) {
 	// Open source file
 	f, err := os.Open(path)
@@ -277,3 +545,45 @@ func timesAreEqual(metadataTimeString string, parsedTime *time.Time) bool {
 	metadataTime := ps.getParsedDate(metadataTimeString)
 	return metadataTime.Equal(*parsedTime)
 }
+
+func Test_readingTime(t *testing.T) {
+	// At 60 words per minute, every word takes exactly one second
+	scenarios := map[string]int{
+		"腾讯新闻，事实派。":                  7,
+		"この記事はReadabilityのテストです":     12,
+		"ภาษาไทย":                    3,
+		"สวัสดีครับ":                 3,
+		"中文 and English mixed":       5,
+		"Hello 世界 and ไทย text":      6,
+		"Readability 测试 ภาษาไทย 完成。": 8,
+	}
+
+	for text, expected := range scenarios {
+		parser := NewParser()
+		parser.WordsPerMinute = 60
+		article, err := parser.Parse(strings.NewReader("<html><body><p>"+text+"</p></body></html>"), fakeHostURL)
+		if err != nil {
+			t.Fatalf("failed to parse %q: %v", text, err)
+		}
+
+		if article.WordCount != expected || article.ReadingTime != time.Duration(expected)*time.Second {
+			t.Errorf("\n"+
+				"text : %q\n"+
+				"want : %d words in %v\n"+
+				"got  : %d words in %v", text, expected, time.Duration(expected)*time.Second,
+				article.WordCount, article.ReadingTime)
+		}
+	}
+
+	source := loremParagraph(60)
+	parser := NewParser()
+	parser.WordsPerMinute = 120
+	article, err := parser.Parse(strings.NewReader(source), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if article.WordCount != 480 || article.ReadingTime != 4*time.Minute {
+		t.Errorf("want 480 words in 4m0s, got %d words in %v", article.WordCount, article.ReadingTime)
+	}
+}
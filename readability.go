@@ -9,11 +9,12 @@
 package readability
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
 	nurl "net/url"
-	"strings"
 	"time"
 
 	"golang.org/x/net/html"
@@ -33,41 +34,204 @@ func FromDocument(doc *html.Node, pageURL *nurl.URL) (Article, error) {
 	return parser.ParseDocument(doc, pageURL)
 }
 
+// RequestWith modifies the HTTP request before it's sent.
 type RequestWith func(r *http.Request)
 
 // FromURL fetch the web page from specified url then parses the response to find
 // the readable content.
 func FromURL(pageURL string, timeout time.Duration, requestModifiers ...RequestWith) (Article, error) {
+	return FromURLContext(context.Background(), pageURL, timeout, requestModifiers...)
+}
+
+// FromURLContext is like `FromURL`, but the context is used for both fetching
+// and parsing the page. This way the whole process can be given a deadline
+// that is independent from the HTTP timeout.
+func FromURLContext(ctx context.Context, pageURL string, timeout time.Duration, requestModifiers ...RequestWith) (Article, error) {
+	return FromURLWithOptions(ctx, pageURL,
+		WithTimeout(timeout),
+		WithRequestModifiers(requestModifiers...))
+}
+
+// URLOption is the option for `FromURLWithOptions`.
+type URLOption func(*urlOptions)
+
+type urlOptions struct {
+	fetcher          Fetcher
+	client           *http.Client
+	timeout          time.Duration
+	requestModifiers []RequestWith
+	parser           *Parser
+	checkReaderable  bool
+	maxBodySize      int64
+}
+
+// WithFetcher sets the fetcher that is used to download the page, e.g. to
+// read the page from a cache or to render it using a headless browser.
+// If it's set, the HTTP client, timeout and request modifiers are ignored.
+func WithFetcher(fetcher Fetcher) URLOption {
+	return func(o *urlOptions) { o.fetcher = fetcher }
+}
+
+// WithHTTPClient sets the HTTP client that is used to download the page,
+// e.g. to use a proxy, a cookie jar or a custom TLS config.
+func WithHTTPClient(client *http.Client) URLOption {
+	return func(o *urlOptions) { o.client = client }
+}
+
+// WithTimeout sets the timeout of the default HTTP client. It's ignored
+// if a custom HTTP client is set.
+func WithTimeout(timeout time.Duration) URLOption {
+	return func(o *urlOptions) { o.timeout = timeout }
+}
+
+// WithRequestModifiers adds the modifiers that are applied to the HTTP
+// request before it's sent.
+func WithRequestModifiers(requestModifiers ...RequestWith) URLOption {
+	return func(o *urlOptions) {
+		o.requestModifiers = append(o.requestModifiers, requestModifiers...)
+	}
+}
+
+// WithParser sets the parser that is used to parse the page. If the parser
+// doesn't have `PageFetcher`, the fetcher of the page is used for it.
+func WithParser(parser *Parser) URLOption {
+	return func(o *urlOptions) { o.parser = parser }
+}
+
+// WithReaderableCheck makes sure the page is readable using `Parser.CheckDocument`
+// before parsing it, and returns error if it's not.
+func WithReaderableCheck() URLOption {
+	return func(o *urlOptions) { o.checkReaderable = true }
+}
+
+// WithMaxBodySize sets the max size in bytes of the page that will be
+// parsed. Larger page will be rejected with `ErrBodyTooLarge`. By default,
+// the size is not limited.
+func WithMaxBodySize(size int64) URLOption {
+	return func(o *urlOptions) { o.maxBodySize = size }
+}
+
+// FromURLWithOptions fetch the web page from specified url using the fetcher
+// in the options, then parses the response to find the readable content.
+// By default, the page is downloaded using `HTTPFetcher` and parsed using
+// the default parser.
+func FromURLWithOptions(ctx context.Context, pageURL string, options ...URLOption) (Article, error) {
+	page, err := fetchPage(ctx, pageURL, options)
+	if err != nil {
+		return Article{}, err
+	}
+
+	if page.opts.checkReaderable && !page.parser.CheckDocument(page.doc) {
+		return Article{}, ErrNotReadable
+	}
+
+	article, err := page.parser.ParseDocumentContext(ctx, page.doc, page.url)
+	if err != nil {
+		return Article{}, err
+	}
+
+	article.Encoding = page.encoding
+	return article, nil
+}
+
+// ExplainURL is like `FromURLWithOptions`, but instead of the article it
+// returns the document annotated by `Parser.ExplainDocument`.
+func ExplainURL(ctx context.Context, pageURL string, options ...URLOption) (*html.Node, error) {
+	page, err := fetchPage(ctx, pageURL, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return page.parser.ExplainDocument(page.doc, page.url)
+}
+
+// fetchedPage is the page that is downloaded by `fetchPage`.
+type fetchedPage struct {
+	doc      *html.Node
+	url      *nurl.URL
+	encoding string
+	parser   Parser
+	opts     urlOptions
+}
+
+// fetchPage fetches the web page using the fetcher in the options, then
+// decodes it into a document. The returned parser is a copy of the parser
+// in the options, with the hints from the response headers.
+func fetchPage(ctx context.Context, pageURL string, options []URLOption) (fetchedPage, error) {
 	// Make sure URL is valid
 	parsedURL, err := nurl.ParseRequestURI(pageURL)
 	if err != nil {
-		return Article{}, fmt.Errorf("failed to parse URL: %v", err)
+		return fetchedPage{}, fmt.Errorf("%w: %w", ErrInvalidURL, err)
 	}
 
-	// Fetch page from URL
-	client := &http.Client{Timeout: timeout}
-	req, err := http.NewRequest("GET", pageURL, nil)
-	for _, modifer := range requestModifiers {
-		modifer(req)
+	// Apply the options
+	var opts urlOptions
+	for _, option := range options {
+		option(&opts)
 	}
-	if err != nil {
-		return Article{}, fmt.Errorf("failed to fetch the page: %v", err)
+
+	fetcher := opts.fetcher
+	if fetcher == nil {
+		client := opts.client
+		if client == nil {
+			client = &http.Client{Timeout: opts.timeout}
+		}
+		fetcher = HTTPFetcher{Client: client, RequestModifiers: opts.requestModifiers}
+	}
+
+	// Copy the parser, so the custom parser is kept untouched
+	parser := NewParser()
+	if opts.parser != nil {
+		parser = *opts.parser
+	}
+
+	if parser.PageFetcher == nil {
+		parser.PageFetcher = fetcher
 	}
-	resp, err := client.Do(req)
+
+	// The next pages of the article are limited as well
+	parser.maxBodySize = opts.maxBodySize
+
+	// Fetch page from URL
+	resp, err := fetcher.Fetch(ctx, parsedURL)
 	if err != nil {
-		return Article{}, fmt.Errorf("failed to fetch the page: %v", err)
+		return fetchedPage{}, fmt.Errorf("failed to fetch the page: %w", err)
 	}
 	defer resp.Body.Close()
 
-	// Make sure content type is HTML
-	cp := resp.Header.Get("Content-Type")
-	if !strings.Contains(cp, "text/html") {
-		return Article{}, fmt.Errorf("URL is not a HTML document")
+	// The page might be redirected, so use the final URL
+	if resp.Request != nil && resp.Request.URL != nil {
+		parsedURL = resp.Request.URL
 	}
 
-	// Parse content
-	parser := NewParser()
-	return parser.Parse(resp.Body, parsedURL)
+	// Make sure the response is a valid HTML page
+	content, err := readResponse(resp, parsedURL, opts.maxBodySize)
+	if err != nil {
+		return fetchedPage{}, err
+	}
+
+	// Parse content, using the charset declared by the server as hint
+	if parser.CharsetHint == "" {
+		parser.CharsetHint = contentTypeCharset(resp.Header.Get("Content-Type"))
+	}
+
+	// Use the language declared by the server as hint as well
+	if parser.LanguageHint == "" {
+		parser.LanguageHint = resp.Header.Get("Content-Language")
+	}
+
+	doc, encoding, err := parser.parseHTML(bytes.NewReader(content), parser.CharsetHint)
+	if err != nil {
+		return fetchedPage{}, fmt.Errorf("failed to parse input: %w", err)
+	}
+
+	return fetchedPage{
+		doc:      doc,
+		url:      parsedURL,
+		encoding: encoding,
+		parser:   parser,
+		opts:     opts,
+	}, nil
 }
 
 // Check checks whether the input is readable without parsing the whole thing. It's the
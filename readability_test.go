@@ -0,0 +1,119 @@
+package readability
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	nurl "net/url"
+	"strings"
+	"testing"
+
+	"github.com/go-shiori/dom"
+)
+
+func Test_FromURLWithOptions(t *testing.T) {
+	content := loremParagraph(20)
+	pages := map[string]string{
+		"http://fakehost/article": `<html><body><article>` + content +
+			`<p>Read <a href="other">the other article</a>.</p>` + content + `</article></body></html>`,
+		"http://fakehost/empty": `<html><body><div>Nothing here</div></body></html>`,
+	}
+
+	var fetched []string
+	fetcher := FetcherFunc(func(ctx context.Context, pageURL *nurl.URL) (*http.Response, error) {
+		fetched = append(fetched, pageURL.String())
+
+		// Pretend the article is redirected to another directory
+		finalURL, _ := nurl.Parse("http://fakehost/redirected/" + strings.TrimPrefix(pageURL.Path, "/"))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {"text/html; charset=utf-8"}},
+			Body:       io.NopCloser(strings.NewReader(pages[pageURL.String()])),
+			Request:    &http.Request{URL: finalURL},
+		}, nil
+	})
+
+	article, err := FromURLWithOptions(context.Background(), "http://fakehost/article", WithFetcher(fetcher))
+	if err != nil {
+		t.Fatalf("failed to parse article: %v", err)
+	}
+
+	if len(fetched) != 1 || fetched[0] != "http://fakehost/article" {
+		t.Errorf("fetched pages, want %q got %q", []string{"http://fakehost/article"}, fetched)
+	}
+
+	if expected := "http://fakehost/redirected/article"; article.URL != expected {
+		t.Errorf("article URL, want %q got %q", expected, article.URL)
+	}
+
+	if expected := `href="http://fakehost/redirected/other"`; !strings.Contains(article.Content, expected) {
+		t.Errorf("link is not resolved against the final URL, want %s in %s", expected, article.Content)
+	}
+
+	doc, err := ExplainURL(context.Background(), "http://fakehost/article", WithFetcher(fetcher))
+	if err != nil {
+		t.Fatalf("failed to explain article: %v", err)
+	}
+
+	if dom.QuerySelector(doc, "article[data-readability-selected]") == nil {
+		t.Errorf("explained article, want the article element to be selected")
+	}
+
+	_, err = FromURLWithOptions(context.Background(), "http://fakehost/empty",
+		WithFetcher(fetcher), WithReaderableCheck())
+	if !errors.Is(err, ErrNotReadable) {
+		t.Errorf("page without article, want ErrNotReadable got %v", err)
+	}
+}
+
+func Test_errors(t *testing.T) {
+	fetcher := FetcherFunc(func(ctx context.Context, pageURL *nurl.URL) (*http.Response, error) {
+		if pageURL.Path == "/timeout" {
+			return nil, context.DeadlineExceeded
+		}
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {"application/pdf"}},
+			Body:       io.NopCloser(strings.NewReader("%PDF-1.4")),
+		}, nil
+	})
+
+	ctx := context.Background()
+	if _, err := FromURLWithOptions(ctx, "not a url", WithFetcher(fetcher)); !errors.Is(err, ErrInvalidURL) {
+		t.Errorf("invalid URL, want ErrInvalidURL got %v", err)
+	}
+
+	if _, err := FromURLWithOptions(ctx, "http://fakehost/file.pdf", WithFetcher(fetcher)); !errors.Is(err, ErrNotHTML) {
+		t.Errorf("PDF file, want ErrNotHTML got %v", err)
+	}
+
+	if _, err := ExplainURL(ctx, "http://fakehost/file.pdf", WithFetcher(fetcher)); !errors.Is(err, ErrNotHTML) {
+		t.Errorf("explained PDF file, want ErrNotHTML got %v", err)
+	}
+
+	if _, err := FromURLWithOptions(ctx, "http://fakehost/timeout", WithFetcher(fetcher)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("fetch error, want context.DeadlineExceeded got %v", err)
+	}
+
+	parser := NewParser()
+	parser.MaxElemsToParse = 5
+	_, err := parser.Parse(strings.NewReader("<div><p>1</p><p>2</p><p>3</p></div>"), fakeHostURL)
+
+	var tooManyErr *TooManyElementsError
+	if !errors.Is(err, ErrTooManyElements) || !errors.As(err, &tooManyErr) || tooManyErr.Count != 7 {
+		t.Errorf("too many elements, want TooManyElementsError with 7 elements got %v", err)
+	}
+
+	parser = NewParser()
+	source := "<html><body></body></html>"
+	if _, err := parser.Parse(strings.NewReader(source), fakeHostURL); err != nil {
+		t.Errorf("empty page without RequireContent, want no error got %v", err)
+	}
+
+	parser.RequireContent = true
+	if _, err := parser.Parse(strings.NewReader(source), fakeHostURL); !errors.Is(err, ErrNoContent) {
+		t.Errorf("empty page with RequireContent, want ErrNoContent got %v", err)
+	}
+}
@@ -0,0 +1,479 @@
+package readability
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+var (
+	rxMdEscape       = regexp.MustCompile("([\\\\`*_\\[\\]])")
+	rxMdLineStart    = regexp.MustCompile(`(?m)^(\s*)([#>+-]|\d+[.)])(\s|$)`)
+	rxMdSpaces       = regexp.MustCompile(`[ \t\n\r\f]+`)
+	rxMdBlankLines   = regexp.MustCompile(`\n{3,}`)
+	rxMdCodeLanguage = regexp.MustCompile(`(?:^|\s)(?:language|lang)-(\S+)`)
+)
+
+// mdHardBreak is a placeholder for <br> used while the inline content is
+// still being normalized, so the line break survives whitespace collapsing.
+const mdHardBreak = "\uE000"
+
+// ToMarkdown renders the specified node and its descendants as CommonMark
+// with a few GitHub Flavored Markdown extensions (tables and strikethrough).
+// It's intended to be used with `Article.Node`, which already has relative
+// URIs converted to absolute ones by the parser.
+func ToMarkdown(node *html.Node) string {
+	if node == nil {
+		return ""
+	}
+
+	var result string
+	switch {
+	case node.Type == html.DocumentNode:
+		result = strings.Join(mdBlocks(node), "\n\n")
+	case isRenderedInline(node):
+		result = mdParagraph(mdInline(node))
+	default:
+		result = strings.Join(mdBlock(node), "\n\n")
+	}
+
+	result = rxMdBlankLines.ReplaceAllString(result, "\n\n")
+	return strings.TrimSpace(result)
+}
+
+// Markdown returns the article content rendered as Markdown.
+func (article Article) Markdown() string {
+	return ToMarkdown(article.Node)
+}
+
+// mdBlocks renders the children of node into a list of Markdown blocks.
+// Consecutive inline children are merged into a single paragraph.
+func mdBlocks(node *html.Node) []string {
+	var blocks []string
+	var inline strings.Builder
+
+	flush := func() {
+		if paragraph := mdParagraph(inline.String()); paragraph != "" {
+			blocks = append(blocks, paragraph)
+		}
+		inline.Reset()
+	}
+
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && !isRenderedInline(child) {
+			flush()
+			blocks = append(blocks, mdBlock(child)...)
+			continue
+		}
+		inline.WriteString(mdInline(child))
+	}
+
+	flush()
+	return blocks
+}
+
+// mdBlock renders a single block level element.
+func mdBlock(node *html.Node) []string {
+	switch tagName := dom.TagName(node); tagName {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		level, _ := strconv.Atoi(tagName[1:])
+		text := mdParagraph(mdInlineChildren(node))
+		if text == "" {
+			return nil
+		}
+		text = strings.ReplaceAll(text, "  \n", " ")
+		return []string{strings.Repeat("#", level) + " " + text}
+
+	case "p":
+		if paragraph := mdParagraph(mdInlineChildren(node)); paragraph != "" {
+			return []string{paragraph}
+		}
+		return nil
+
+	case "hr":
+		return []string{"---"}
+
+	case "pre":
+		return []string{mdCodeBlock(node)}
+
+	case "blockquote":
+		content := strings.Join(mdBlocks(node), "\n\n")
+		if content == "" {
+			return nil
+		}
+		return []string{mdPrefixLines(content, "> ", ">")}
+
+	case "ul", "ol":
+		if list := mdList(node); list != "" {
+			return []string{list}
+		}
+		return nil
+
+	case "table":
+		if !isMarkdownDataTable(node) {
+			return mdBlocks(node)
+		}
+
+		if table, ok := mdTable(node); ok {
+			return []string{table}
+		}
+		return mdBlocks(node)
+
+	case "figcaption", "caption":
+		if caption := mdParagraph(mdInlineChildren(node)); caption != "" {
+			return []string{"*" + caption + "*"}
+		}
+		return nil
+
+	case "dt":
+		if term := mdParagraph(mdInlineChildren(node)); term != "" {
+			return []string{"**" + term + "**"}
+		}
+		return nil
+
+	case "video", "iframe":
+		if media := mdParagraph(mdInline(node)); media != "" {
+			return []string{media}
+		}
+		return nil
+
+	case "head", "script", "style", "noscript", "template", "svg", "math", "select",
+		"button", "input", "textarea", "object", "form":
+		return nil
+
+	default:
+		return mdBlocks(node)
+	}
+}
+
+// mdList renders <ul> and <ol> elements, including the nested ones.
+func mdList(list *html.Node) string {
+	ordered := dom.TagName(list) == "ol"
+	number := 1
+	if start, err := strconv.Atoi(dom.GetAttribute(list, "start")); err == nil && ordered {
+		number = start
+	}
+
+	var items []string
+	for _, item := range dom.Children(list) {
+		if dom.TagName(item) != "li" {
+			continue
+		}
+
+		marker := "- "
+		if ordered {
+			marker = strconv.Itoa(number) + ". "
+			number++
+		}
+
+		content := strings.Join(mdBlocks(item), "\n")
+		indent := strings.Repeat(" ", len(marker))
+		content = strings.TrimPrefix(mdPrefixLines(content, indent, ""), indent)
+		items = append(items, strings.TrimRight(marker+content, " "))
+	}
+
+	return strings.Join(items, "\n")
+}
+
+// mdCodeBlock renders <pre> as a fenced code block. The fence is made
+// longer than any backtick run inside the code so it can't be closed early.
+func mdCodeBlock(pre *html.Node) string {
+	language := ""
+	for _, node := range append([]*html.Node{pre}, dom.GetElementsByTagName(pre, "code")...) {
+		if parts := rxMdCodeLanguage.FindStringSubmatch(dom.ClassName(node)); len(parts) > 1 {
+			language = parts[1]
+			break
+		}
+	}
+
+	code := strings.Trim(dom.TextContent(pre), "\n")
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+
+	return fence + language + "\n" + code + "\n" + fence
+}
+
+// isMarkdownDataTable checks whether the table is a data table, which is
+// rendered as GFM table. Tables that are only used for layout are rendered
+// as their content instead. It uses the mark set by `markDataTables` if the
+// table still has it, otherwise the same heuristic is used since the mark
+// is removed from the article content.
+func isMarkdownDataTable(table *html.Node) bool {
+	if dom.HasAttribute(table, "data-readability-table") {
+		return true
+	}
+
+	var ps Parser
+	return ps.isDataTable(table)
+}
+
+// mdTable renders a table as GFM table. Tables that can't be represented
+// in GFM (nested tables or block content inside cells) are rejected.
+func mdTable(table *html.Node) (string, bool) {
+	if len(dom.GetElementsByTagName(table, "table")) > 0 {
+		return "", false
+	}
+
+	var rows [][]string
+	var caption string
+	columns := 0
+	for _, tr := range dom.GetElementsByTagName(table, "tr") {
+		var row []string
+		for _, cell := range dom.Children(tr) {
+			if tag := dom.TagName(cell); tag != "td" && tag != "th" {
+				continue
+			}
+
+			if len(dom.QuerySelectorAll(cell, "p, div, ul, ol, pre, blockquote")) > 0 {
+				return "", false
+			}
+
+			text := mdParagraph(mdInlineChildren(cell))
+			text = strings.ReplaceAll(text, "  \n", " ")
+			text = strings.ReplaceAll(text, "|", `\|`)
+			row = append(row, text)
+
+			colSpan, _ := strconv.Atoi(dom.GetAttribute(cell, "colspan"))
+			for i := 1; i < colSpan; i++ {
+				row = append(row, "")
+			}
+		}
+
+		if len(row) == 0 {
+			continue
+		}
+
+		if len(row) > columns {
+			columns = len(row)
+		}
+		rows = append(rows, row)
+	}
+
+	if len(rows) == 0 || columns == 0 {
+		return "", false
+	}
+
+	if captions := dom.GetElementsByTagName(table, "caption"); len(captions) > 0 {
+		caption = mdParagraph(mdInlineChildren(captions[0]))
+	}
+
+	var sb strings.Builder
+	if caption != "" {
+		sb.WriteString("*" + caption + "*\n\n")
+	}
+
+	writeRow := func(row []string) {
+		for len(row) < columns {
+			row = append(row, "")
+		}
+		sb.WriteString("| " + strings.Join(row, " | ") + " |\n")
+	}
+
+	writeRow(rows[0])
+	separator := make([]string, columns)
+	for i := range separator {
+		separator[i] = "---"
+	}
+	writeRow(separator)
+	for _, row := range rows[1:] {
+		writeRow(row)
+	}
+
+	return strings.TrimSuffix(sb.String(), "\n"), true
+}
+
+// mdInlineChildren renders every child of node as inline content.
+func mdInlineChildren(node *html.Node) string {
+	var sb strings.Builder
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		sb.WriteString(mdInline(child))
+	}
+	return sb.String()
+}
+
+// mdInline renders phrasing content. Block elements that are nested
+// inside inline elements are flattened into their text.
+func mdInline(node *html.Node) string {
+	switch node.Type {
+	case html.TextNode:
+		return mdEscape(rxMdSpaces.ReplaceAllString(node.Data, " "))
+	case html.ElementNode:
+	default:
+		return ""
+	}
+
+	switch dom.TagName(node) {
+	case "br":
+		return mdHardBreak
+
+	case "a":
+		content := mdInlineChildren(node)
+		href := strings.TrimSpace(dom.GetAttribute(node, "href"))
+		if href == "" || strings.TrimSpace(content) == "" {
+			return content
+		}
+		return mdWrapSpaces(content, func(s string) string {
+			return "[" + s + "](" + mdDestination(href) + mdTitle(node) + ")"
+		})
+
+	case "img":
+		src := strings.TrimSpace(dom.GetAttribute(node, "src"))
+		if src == "" {
+			return ""
+		}
+		alt := mdEscape(rxMdSpaces.ReplaceAllString(dom.GetAttribute(node, "alt"), " "))
+		return "![" + strings.TrimSpace(alt) + "](" + mdDestination(src) + mdTitle(node) + ")"
+
+	case "video", "audio", "iframe", "embed":
+		src := strings.TrimSpace(dom.GetAttribute(node, "src"))
+		if src == "" {
+			if sources := dom.GetElementsByTagName(node, "source"); len(sources) > 0 {
+				src = strings.TrimSpace(dom.GetAttribute(sources[0], "src"))
+			}
+		}
+		if src == "" {
+			return ""
+		}
+		return "[" + mdEscape(src) + "](" + mdDestination(src) + ")"
+
+	case "picture":
+		if imgs := dom.GetElementsByTagName(node, "img"); len(imgs) > 0 {
+			return mdInline(imgs[0])
+		}
+		return ""
+
+	case "strong", "b":
+		return mdWrapDelimiter(mdInlineChildren(node), "**")
+
+	case "em", "i", "cite", "dfn":
+		return mdWrapDelimiter(mdInlineChildren(node), "*")
+
+	case "del", "s", "strike":
+		return mdWrapDelimiter(mdInlineChildren(node), "~~")
+
+	case "code", "kbd", "samp", "tt":
+		code := rxMdSpaces.ReplaceAllString(dom.TextContent(node), " ")
+		if strings.TrimSpace(code) == "" {
+			return code
+		}
+
+		fence := "`"
+		for strings.Contains(code, fence) {
+			fence += "`"
+		}
+		if strings.HasPrefix(code, "`") || strings.HasSuffix(code, "`") {
+			code = " " + code + " "
+		}
+		return fence + code + fence
+
+	case "script", "style", "noscript", "template", "button", "input", "select", "textarea":
+		return ""
+
+	default:
+		if isRenderedInline(node) {
+			return mdInlineChildren(node)
+		}
+		return " " + mdInlineChildren(node) + " "
+	}
+}
+
+// mdParagraph normalizes the whitespace of rendered inline content and
+// converts the hard break placeholders into real Markdown line breaks.
+func mdParagraph(s string) string {
+	s = rxMdSpaces.ReplaceAllString(s, " ")
+	s = strings.ReplaceAll(s, " "+mdHardBreak, mdHardBreak)
+	s = strings.ReplaceAll(s, mdHardBreak+" ", mdHardBreak)
+	s = strings.Trim(s, " "+mdHardBreak)
+	s = strings.ReplaceAll(s, mdHardBreak, "  \n")
+
+	// Escape text that would otherwise be parsed as heading, quote or list
+	return rxMdLineStart.ReplaceAllStringFunc(s, func(line string) string {
+		parts := rxMdLineStart.FindStringSubmatch(line)
+		marker := parts[2]
+		if last := len(marker) - 1; marker[last] == '.' || marker[last] == ')' {
+			marker = marker[:last] + `\` + marker[last:]
+		} else {
+			marker = `\` + marker
+		}
+		return parts[1] + marker + parts[3]
+	})
+}
+
+// mdWrapDelimiter wraps s with emphasis delimiter. Leading and trailing
+// whitespace is moved outside of the delimiter, otherwise CommonMark
+// won't treat it as emphasis.
+func mdWrapDelimiter(s string, delimiter string) string {
+	return mdWrapSpaces(s, func(s string) string {
+		return delimiter + s + delimiter
+	})
+}
+
+// mdWrapSpaces calls wrap with s stripped from surrounding whitespace,
+// then puts the whitespace back around the wrapped result.
+func mdWrapSpaces(s string, wrap func(string) string) string {
+	trimmed := strings.TrimSpace(s)
+	if trimmed == "" {
+		return s
+	}
+
+	leading := s[:strings.Index(s, trimmed)]
+	trailing := s[len(leading)+len(trimmed):]
+	return leading + wrap(trimmed) + trailing
+}
+
+// mdEscape escapes characters that have special meaning in Markdown.
+func mdEscape(s string) string {
+	return rxMdEscape.ReplaceAllString(s, `\$1`)
+}
+
+// mdDestination formats uri as link destination, using angle brackets
+// when the uri contains characters that would break the link.
+func mdDestination(uri string) string {
+	if strings.ContainsAny(uri, " ()<>") {
+		return "<" + strings.NewReplacer("<", "%3C", ">", "%3E").Replace(uri) + ">"
+	}
+	return uri
+}
+
+// mdTitle returns the link title part for node, if it has any.
+func mdTitle(node *html.Node) string {
+	title := strings.TrimSpace(dom.GetAttribute(node, "title"))
+	if title == "" {
+		return ""
+	}
+	return ` "` + strings.ReplaceAll(title, `"`, `\"`) + `"`
+}
+
+// mdPrefixLines adds prefix to every non-empty line in s. Empty lines
+// are replaced with emptyPrefix.
+func mdPrefixLines(s string, prefix string, emptyPrefix string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = emptyPrefix
+		} else {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// isRenderedInline determines whether node is rendered as inline content
+// by the Markdown and plain text renderers.
+func isRenderedInline(node *html.Node) bool {
+	if node.Type != html.ElementNode {
+		return true
+	}
+
+	switch tagName := dom.TagName(node); tagName {
+	case "a", "del", "ins", "picture", "s", "strike", "tt", "u", "big", "font":
+		return true
+	default:
+		return indexOf(phrasingElems, tagName) != -1
+	}
+}
@@ -0,0 +1,83 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-shiori/dom"
+)
+
+func Test_ToMarkdown(t *testing.T) {
+	scenarios := map[string]string{
+		`<h2>Hello <em>world</em></h2><p>First   paragraph<br>next line.</p>`: "" +
+			"## Hello *world*\n\n" +
+			"First paragraph  \nnext line.",
+
+		`<p>Go to <a href="http://example.com/a" title="Example">the <b>site</b> </a>now.</p>`: "" +
+			`Go to [the **site**](http://example.com/a "Example") now.`,
+
+		`<p><img src="http://example.com/a b.png" alt="An image"></p>`: "" +
+			`![An image](<http://example.com/a b.png>)`,
+
+		`<ul><li>One</li><li>Two<ol start="3"><li>Three</li><li>Four</li></ol></li></ul>`: "" +
+			"- One\n" +
+			"- Two\n" +
+			"  3. Three\n" +
+			"  4. Four",
+
+		`<blockquote><p>Quote</p><p>Second</p></blockquote>`: "" +
+			"> Quote\n" +
+			">\n" +
+			"> Second",
+
+		"<pre><code class=\"language-go\">fmt.Println(\"```\")\n</code></pre>": "" +
+			"````go\n" +
+			"fmt.Println(\"```\")\n" +
+			"````",
+
+		`<p>Use <code>a_b</code> or *stars* and 1_000.</p>`: "" +
+			"Use `a_b` or \\*stars\\* and 1\\_000.",
+
+		`<table><tr><th>Name</th><th>Value</th></tr><tr><td>a|b</td><td>1</td></tr></table>`: "" +
+			"| Name | Value |\n" +
+			"| --- | --- |\n" +
+			"| a\\|b | 1 |",
+
+		`<table data-readability-table="true"><tr><td>Marked</td><td>table</td></tr></table>`: "" +
+			"| Marked | table |\n" +
+			"| --- | --- |",
+
+		`<table><tr><td>Layout</td><td>table</td></tr></table>`: "" +
+			"Layout\n\n" +
+			"table",
+
+		`<table><tr><th>Name</th><th>Value</th></tr><tr><td><p>Block</p></td><td>1</td></tr></table>`: "" +
+			"Name\n\n" +
+			"Value\n\n" +
+			"Block\n\n" +
+			"1",
+
+		`<figure><img src="http://example.com/a.png" alt=""><figcaption>The caption</figcaption></figure>`: "" +
+			"![](http://example.com/a.png)\n\n" +
+			"*The caption*",
+
+		`<p>1. Not a list</p><p># Not a heading</p>`: "" +
+			"1\\. Not a list\n\n" +
+			"\\# Not a heading",
+	}
+
+	for source, expected := range scenarios {
+		doc, err := dom.Parse(strings.NewReader(source))
+		if err != nil {
+			t.Fatalf("failed to parse %q: %v", source, err)
+		}
+
+		body := dom.GetElementsByTagName(doc, "body")[0]
+		if result := ToMarkdown(body); result != expected {
+			t.Errorf("\n"+
+				"source : %s\n"+
+				"want   : %q\n"+
+				"got    : %q", source, expected, result)
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package readability
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// txtLineBreak is a placeholder for <br> used while the inline content is
+// still being normalized, so the line break survives whitespace collapsing.
+const txtLineBreak = "\uE000"
+
+// txtQuoteIndent is the indentation used for the content of block quotes.
+const txtQuoteIndent = "    "
+
+// ToText renders the specified node and its descendants as plain text while
+// keeping the structure of the document: block elements are separated by a
+// blank line, list items are prefixed with a bullet or their number, and
+// block quotes are indented. If wrapWidth is bigger than zero, paragraphs
+// are wrapped so each line is at most wrapWidth characters long, unless a
+// single word is longer than that. Preformatted text is never wrapped.
+func ToText(node *html.Node, wrapWidth int) string {
+	if node == nil {
+		return ""
+	}
+
+	var result string
+	switch {
+	case node.Type == html.DocumentNode:
+		result = strings.Join(txtBlocks(node, wrapWidth), "\n\n")
+	case isRenderedInline(node):
+		result = txtParagraph(txtInline(node), wrapWidth)
+	default:
+		result = strings.Join(txtBlock(node, wrapWidth), "\n\n")
+	}
+
+	return strings.Trim(result, "\n")
+}
+
+// Text returns the article content rendered as structured plain text.
+// See `ToText` for the meaning of wrapWidth.
+func (article Article) Text(wrapWidth int) string {
+	return ToText(article.Node, wrapWidth)
+}
+
+// txtBlocks renders the children of node into a list of text blocks.
+// Consecutive inline children are merged into a single paragraph.
+func txtBlocks(node *html.Node, width int) []string {
+	var blocks []string
+	var inline strings.Builder
+
+	flush := func() {
+		if paragraph := txtParagraph(inline.String(), width); paragraph != "" {
+			blocks = append(blocks, paragraph)
+		}
+		inline.Reset()
+	}
+
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && !isRenderedInline(child) {
+			flush()
+			blocks = append(blocks, txtBlock(child, width)...)
+			continue
+		}
+		inline.WriteString(txtInline(child))
+	}
+
+	flush()
+	return blocks
+}
+
+// txtBlock renders a single block level element.
+func txtBlock(node *html.Node, width int) []string {
+	switch dom.TagName(node) {
+	case "pre":
+		if code := strings.Trim(dom.TextContent(node), "\n"); strings.TrimSpace(code) != "" {
+			return []string{code}
+		}
+		return nil
+
+	case "blockquote":
+		content := strings.Join(txtBlocks(node, txtInnerWidth(width, txtQuoteIndent)), "\n\n")
+		if content == "" {
+			return nil
+		}
+		return []string{mdPrefixLines(content, txtQuoteIndent, "")}
+
+	case "ul", "ol":
+		if list := txtList(node, width); list != "" {
+			return []string{list}
+		}
+		return nil
+
+	case "table":
+		if table, ok := txtTable(node); ok {
+			return []string{table}
+		}
+		return txtBlocks(node, width)
+
+	case "hr", "head", "script", "style", "noscript", "template", "svg", "math",
+		"select", "button", "input", "textarea", "object", "form", "iframe", "video":
+		return nil
+
+	default:
+		return txtBlocks(node, width)
+	}
+}
+
+// txtList renders <ul> and <ol> elements. The content of each item is
+// indented so it's aligned with the text after the bullet or number.
+func txtList(list *html.Node, width int) string {
+	ordered := dom.TagName(list) == "ol"
+	number := 1
+	if start, err := strconv.Atoi(dom.GetAttribute(list, "start")); err == nil && ordered {
+		number = start
+	}
+
+	var items []string
+	for _, item := range dom.Children(list) {
+		if dom.TagName(item) != "li" {
+			continue
+		}
+
+		marker := "- "
+		if ordered {
+			marker = strconv.Itoa(number) + ". "
+			number++
+		}
+
+		indent := strings.Repeat(" ", len(marker))
+		content := strings.Join(txtBlocks(item, txtInnerWidth(width, indent)), "\n")
+		content = strings.TrimPrefix(mdPrefixLines(content, indent, ""), indent)
+		items = append(items, strings.TrimRight(marker+content, " "))
+	}
+
+	return strings.Join(items, "\n")
+}
+
+// txtTable renders each table row in its own line, with the cells
+// separated by a vertical bar. Nested tables are rejected.
+func txtTable(table *html.Node) (string, bool) {
+	if len(dom.GetElementsByTagName(table, "table")) > 0 {
+		return "", false
+	}
+
+	var rows []string
+	for _, tr := range dom.GetElementsByTagName(table, "tr") {
+		var cells []string
+		for _, cell := range dom.Children(tr) {
+			if tag := dom.TagName(cell); tag != "td" && tag != "th" {
+				continue
+			}
+
+			text := txtParagraph(txtInline(cell), 0)
+			cells = append(cells, strings.ReplaceAll(text, "\n", " "))
+		}
+
+		if row := strings.TrimSpace(strings.Join(cells, " | ")); row != "" && row != "|" {
+			rows = append(rows, row)
+		}
+	}
+
+	if len(rows) == 0 {
+		return "", false
+	}
+
+	return strings.Join(rows, "\n"), true
+}
+
+// txtInline renders phrasing content. Block elements that are nested
+// inside inline elements are flattened into their text.
+func txtInline(node *html.Node) string {
+	switch node.Type {
+	case html.TextNode:
+		return rxMdSpaces.ReplaceAllString(node.Data, " ")
+	case html.ElementNode:
+	default:
+		return ""
+	}
+
+	var sb strings.Builder
+	switch dom.TagName(node) {
+	case "br":
+		return txtLineBreak
+
+	case "script", "style", "noscript", "template", "button", "input", "select", "textarea":
+		return ""
+
+	default:
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			sb.WriteString(txtInline(child))
+		}
+	}
+
+	if isRenderedInline(node) {
+		return sb.String()
+	}
+	return " " + sb.String() + " "
+}
+
+// txtParagraph normalizes the whitespace of rendered inline content,
+// converts the line break placeholders and wraps the result.
+func txtParagraph(s string, width int) string {
+	s = rxMdSpaces.ReplaceAllString(s, " ")
+	s = strings.ReplaceAll(s, " "+txtLineBreak, txtLineBreak)
+	s = strings.ReplaceAll(s, txtLineBreak+" ", txtLineBreak)
+	s = strings.Trim(s, " "+txtLineBreak)
+
+	lines := strings.Split(s, txtLineBreak)
+	for i, line := range lines {
+		lines[i] = wrapText(line, width)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// txtInnerWidth returns the width that is available for content that will
+// be prefixed with indent. Wrapping stays disabled if width is zero.
+func txtInnerWidth(width int, indent string) int {
+	if width <= 0 {
+		return 0
+	}
+
+	if inner := width - len(indent); inner > 0 {
+		return inner
+	}
+	return 1
+}
+
+// wrapText breaks s into lines that are at most width characters long.
+// Words are never split, so a word longer than width gets its own line.
+func wrapText(s string, width int) string {
+	if width <= 0 || charCount(s) <= width {
+		return s
+	}
+
+	var sb strings.Builder
+	lineLength := 0
+	for _, word := range strings.Fields(s) {
+		wordLength := charCount(word)
+		if lineLength > 0 && lineLength+1+wordLength > width {
+			sb.WriteString("\n")
+			lineLength = 0
+		} else if lineLength > 0 {
+			sb.WriteString(" ")
+			lineLength++
+		}
+
+		sb.WriteString(word)
+		lineLength += wordLength
+	}
+
+	return sb.String()
+}
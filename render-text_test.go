@@ -0,0 +1,69 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-shiori/dom"
+)
+
+func Test_ToText(t *testing.T) {
+	type scenario struct {
+		source    string
+		wrapWidth int
+	}
+
+	scenarios := map[scenario]string{
+		{`<h2>Title</h2><p>First   paragraph<br>next line.</p><div>Loose text</div>`, 0}: "" +
+			"Title\n\n" +
+			"First paragraph\n" +
+			"next line.\n\n" +
+			"Loose text",
+
+		{`<ul><li>One</li><li>Two<ol start="3"><li>Three</li><li>Four</li></ol></li></ul>`, 0}: "" +
+			"- One\n" +
+			"- Two\n" +
+			"  3. Three\n" +
+			"  4. Four",
+
+		{`<blockquote><p>Quoted text</p></blockquote><p>After</p>`, 0}: "" +
+			"    Quoted text\n\n" +
+			"After",
+
+		{"<pre>  indented\n    code</pre>", 5}: "" +
+			"  indented\n" +
+			"    code",
+
+		{`<p>The quick brown fox jumps over the lazy dog</p>`, 16}: "" +
+			"The quick brown\n" +
+			"fox jumps over\n" +
+			"the lazy dog",
+
+		{`<ul><li>The quick brown fox jumps</li></ul><blockquote>over the lazy dog</blockquote>`, 14}: "" +
+			"- The quick\n" +
+			"  brown fox\n" +
+			"  jumps\n\n" +
+			"    over the\n" +
+			"    lazy dog",
+
+		{`<table><tr><th>Name</th><th>Value</th></tr><tr><td>a</td><td>1</td></tr></table>`, 0}: "" +
+			"Name | Value\n" +
+			"a | 1",
+	}
+
+	for s, expected := range scenarios {
+		doc, err := dom.Parse(strings.NewReader(s.source))
+		if err != nil {
+			t.Fatalf("failed to parse %q: %v", s.source, err)
+		}
+
+		body := dom.GetElementsByTagName(doc, "body")[0]
+		if result := ToText(body, s.wrapWidth); result != expected {
+			t.Errorf("\n"+
+				"source : %s\n"+
+				"width  : %d\n"+
+				"want   : %q\n"+
+				"got    : %q", s.source, s.wrapWidth, expected, result)
+		}
+	}
+}
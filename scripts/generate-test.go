@@ -185,6 +185,7 @@ func renderMetadataToFile(original *html.Node, article readability.Article, file
 	metadata := struct {
 		Title      string `json:"title,omitempty"`
 		Byline     string `json:"byline,omitempty"`
+		Dir        string `json:"dir,omitempty"`
 		Excerpt    string `json:"excerpt,omitempty"`
 		Language   string `json:"language,omitempty"`
 		SiteName   string `json:"siteName,omitempty"`
@@ -192,6 +193,7 @@ func renderMetadataToFile(original *html.Node, article readability.Article, file
 	}{
 		Title:      article.Title,
 		Byline:     article.Byline,
+		Dir:        article.Dir,
 		Excerpt:    article.Excerpt,
 		Language:   article.Language,
 		SiteName:   article.SiteName,
@@ -0,0 +1,124 @@
+package readability
+
+import (
+	"fmt"
+	nurl "net/url"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/andybalholm/cascadia"
+	"gopkg.in/yaml.v3"
+)
+
+// SiteRule is a custom extraction rule for the pages of a site, useful for
+// the sites that are consistently mis-extracted by the parser. Every
+// selector is a CSS selector, and the empty ones are ignored.
+type SiteRule struct {
+	// Host is the host pattern of the pages the rule applies to. A plain
+	// host like `example.com` matches the host and all of its subdomains,
+	// while a pattern with wildcard like `blog-*.example.com` is matched
+	// using `path.Match`.
+	Host string `json:"host" yaml:"host"`
+	// Content is the selector of the article content. The matched elements
+	// are used directly as the article, skipping the scoring algorithm. If
+	// nothing matches, the parser falls back to its normal algorithm.
+	Content string `json:"content,omitempty" yaml:"content,omitempty"`
+	// Remove is the selectors of the elements that are always removed.
+	Remove []string `json:"remove,omitempty" yaml:"remove,omitempty"`
+	// Keep is the selectors of the elements that are never removed by the
+	// cleaning rules of the parser. A kept element that is a sibling of
+	// the top candidate is always appended into the article.
+	Keep []string `json:"keep,omitempty" yaml:"keep,omitempty"`
+	// Title is the selector of the article title.
+	Title string `json:"title,omitempty" yaml:"title,omitempty"`
+	// Byline is the selector of the article byline.
+	Byline string `json:"byline,omitempty" yaml:"byline,omitempty"`
+	// Date is the selector of the published date. The date is taken from
+	// the `datetime` or `content` attribute if exist, otherwise from the
+	// text of the element.
+	Date string `json:"date,omitempty" yaml:"date,omitempty"`
+}
+
+// ParseSiteRules parses a list of `SiteRule` from a YAML or JSON document.
+// It returns `ErrInvalidSiteRule` if a rule doesn't have any host or if
+// one of its selectors is invalid.
+func ParseSiteRules(data []byte) ([]SiteRule, error) {
+	// YAML is a superset of JSON, so the same decoder works for both
+	var rules []SiteRule
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to decode site rules: %w", err)
+	}
+
+	for i, rule := range rules {
+		if err := rule.validate(); err != nil {
+			return nil, fmt.Errorf("%w: rule %d: %w", ErrInvalidSiteRule, i, err)
+		}
+	}
+
+	return rules, nil
+}
+
+// LoadSiteRules reads a list of `SiteRule` from a YAML or JSON file.
+func LoadSiteRules(filePath string) ([]SiteRule, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read site rules: %w", err)
+	}
+
+	return ParseSiteRules(data)
+}
+
+// validate checks the host and selectors of the rule.
+func (rule SiteRule) validate() error {
+	if strings.TrimSpace(rule.Host) == "" {
+		return fmt.Errorf("host is empty")
+	}
+
+	if _, err := path.Match(rule.Host, ""); err != nil {
+		return fmt.Errorf("invalid host pattern %q: %w", rule.Host, err)
+	}
+
+	selectors := append([]string{rule.Content, rule.Title, rule.Byline, rule.Date}, rule.Remove...)
+	selectors = append(selectors, rule.Keep...)
+	for _, selector := range selectors {
+		if selector == "" {
+			continue
+		}
+
+		if _, err := cascadia.ParseGroup(selector); err != nil {
+			return fmt.Errorf("invalid selector %q: %w", selector, err)
+		}
+	}
+
+	return nil
+}
+
+// matchHost checks whether the rule applies to the host.
+func (rule SiteRule) matchHost(host string) bool {
+	pattern := strings.ToLower(strings.TrimSpace(rule.Host))
+	host = strings.ToLower(host)
+
+	if strings.ContainsAny(pattern, "*?[") {
+		match, _ := path.Match(pattern, host)
+		return match
+	}
+
+	return host == pattern || strings.HasSuffix(host, "."+pattern)
+}
+
+// findSiteRule returns the first rule that applies to the URL, or nil.
+func findSiteRule(rules []SiteRule, pageURL *nurl.URL) *SiteRule {
+	if pageURL == nil {
+		return nil
+	}
+
+	host := pageURL.Hostname()
+	for i := range rules {
+		if rules[i].matchHost(host) {
+			return &rules[i]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package readability
+
+import (
+	"errors"
+	nurl "net/url"
+	"strings"
+	"testing"
+)
+
+func Test_ParseSiteRules(t *testing.T) {
+	yamlRules := `
+- host: example.com
+  content: "#story"
+  remove: [".ad", ".newsletter"]
+  keep: [".share-quote"]
+  title: h1.headline
+  byline: .author
+  date: time.published
+`
+
+	jsonRules := `[{
+		"host": "example.com",
+		"content": "#story",
+		"remove": [".ad", ".newsletter"],
+		"keep": [".share-quote"],
+		"title": "h1.headline",
+		"byline": ".author",
+		"date": "time.published"
+	}]`
+
+	for _, source := range []string{yamlRules, jsonRules} {
+		rules, err := ParseSiteRules([]byte(source))
+		if err != nil {
+			t.Fatalf("failed to parse rules: %v", err)
+		}
+
+		if len(rules) != 1 || rules[0].Content != "#story" || len(rules[0].Remove) != 2 ||
+			rules[0].Keep[0] != ".share-quote" || rules[0].Date != "time.published" {
+			t.Errorf("unexpected rules %+v from %s", rules, source)
+		}
+	}
+
+	invalidRules := []string{
+		`[{"content": "#story"}]`,
+		`[{"host": "example.com", "remove": ["div["]}]`,
+		`[{"host": "[example.com"}]`,
+	}
+
+	for _, source := range invalidRules {
+		if _, err := ParseSiteRules([]byte(source)); !errors.Is(err, ErrInvalidSiteRule) {
+			t.Errorf("rules %s, want ErrInvalidSiteRule got %v", source, err)
+		}
+	}
+}
+
+func Test_findSiteRule(t *testing.T) {
+	rules := []SiteRule{
+		{Host: "blog-*.example.com", Content: "wildcard"},
+		{Host: "example.com", Content: "plain"},
+	}
+
+	scenarios := map[string]string{
+		"http://example.com/article":          "plain",
+		"http://www.example.com/article":      "plain",
+		"http://EXAMPLE.com:8080/article":     "plain",
+		"http://blog-tech.example.com/post":   "wildcard",
+		"http://notexample.com/article":       "",
+		"http://example.com.evil.org/article": "",
+	}
+
+	for strURL, expected := range scenarios {
+		pageURL, _ := nurl.Parse(strURL)
+
+		var content string
+		if rule := findSiteRule(rules, pageURL); rule != nil {
+			content = rule.Content
+		}
+
+		if content != expected {
+			t.Errorf("\n"+
+				"url  : %s\n"+
+				"want : %q\n"+
+				"got  : %q", strURL, expected, content)
+		}
+	}
+}
+
+func Test_siteRules(t *testing.T) {
+	content := loremParagraph(20)
+	source := `<html lang="en"><head><title>Site title</title></head><body>` +
+		`<h1 class="headline">The real headline</h1>` +
+		`<span class="author">Jane Doe</span>` +
+		`<time class="published" datetime="2024-05-01T10:00:00Z">May 1</time>` +
+		`<div id="comments">` + content + content + content + `</div>` +
+		`<div id="story" dir="rtl"><p>Short story, but it's the one we want.</p>` +
+		`<div class="ad">Buy now</div>` +
+		`<div class="share-quote">Share this quote<aside>Quote source</aside></div>` +
+		`<aside><p>Related stories</p></aside></div>` +
+		`</body></html>`
+
+	parser := NewParser()
+	parser.SiteRules = []SiteRule{{
+		Host:    "fakehost",
+		Content: "#story",
+		Remove:  []string{".ad"},
+		Keep:    []string{".share-quote"},
+		Title:   "h1.headline",
+		Byline:  ".author",
+		Date:    "time.published",
+	}}
+
+	article, err := parser.Parse(strings.NewReader(source), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if article.Title != "The real headline" || article.Byline != "Jane Doe" ||
+		article.PublishedTime == nil || article.PublishedTime.Year() != 2024 {
+		t.Errorf("metadata is not overridden: %q, %q, %v", article.Title, article.Byline, article.PublishedTime)
+	}
+
+	if !strings.Contains(article.TextContent, "the one we want") ||
+		!strings.Contains(article.TextContent, "Share this quote") ||
+		!strings.Contains(article.TextContent, "Quote source") ||
+		strings.Contains(article.TextContent, "Related stories") ||
+		strings.Contains(article.TextContent, "Buy now") ||
+		strings.Contains(article.TextContent, "Lorem ipsum") {
+		t.Errorf("unexpected content from site rule: %q", article.TextContent)
+	}
+
+	if article.Dir != "rtl" || article.Language != "en" {
+		t.Errorf("want rtl direction and en language from site rule content, got %q and %q", article.Dir, article.Language)
+	}
+
+	if strings.Contains(article.Content, keepAttr) {
+		t.Errorf("%s attribute is not removed from content", keepAttr)
+	}
+
+	// If the content selector doesn't match, the usual algorithm is used
+	parser.SiteRules[0].Content = "#missing"
+	article, err = parser.Parse(strings.NewReader(source), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if !strings.Contains(article.TextContent, "Lorem ipsum") {
+		t.Errorf("parser doesn't fall back when content selector doesn't match: %q", article.TextContent)
+	}
+}
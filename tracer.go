@@ -0,0 +1,180 @@
+package readability
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// TraceAction is the kind of decision reported in a `TraceEvent`.
+type TraceAction string
+
+const (
+	// TraceRemove is reported when a node is removed from the document.
+	TraceRemove TraceAction = "remove"
+	// TraceConvert is reported when the tag of a node is changed, or when
+	// a node is replaced by its only child.
+	TraceConvert TraceAction = "convert"
+	// TraceScore is reported when the content score of a node is changed.
+	TraceScore TraceAction = "score"
+	// TraceSelect is reported when a node is selected as (part of) the
+	// article content.
+	TraceSelect TraceAction = "select"
+	// TraceSkip is reported when a node is ignored by a decision, e.g. a
+	// paragraph that is too short to be scored or a sibling of the top
+	// candidate that is not appended into the article.
+	TraceSkip TraceAction = "skip"
+	// TraceInfo is reported for the other events that are not about a
+	// specific node, e.g. when a next page can't be fetched.
+	TraceInfo TraceAction = "info"
+)
+
+// TraceEvent is a decision made by the parser while extracting the article.
+type TraceEvent struct {
+	// Action is the kind of decision.
+	Action TraceAction
+	// Reason explains why the decision is made.
+	Reason string
+	// Node is the node the decision is made for. It's nil for TraceInfo.
+	Node *html.Node
+	// Path is the path of the node at the time the decision is made, e.g.
+	// `html > body > div#main > p:nth-child(2)`. Keep in mind the parser
+	// works on a copy of the document that is modified along the way.
+	Path string
+	// Tag is the new tag name of the node for TraceConvert.
+	Tag string
+	// Score is the new content score of the node for TraceScore.
+	Score float64
+}
+
+// Tracer receives the decisions made by the parser. It can be used to find
+// out why some content is removed from the article.
+type Tracer interface {
+	Trace(event TraceEvent)
+}
+
+// TracerFunc is an adapter to allow the use of ordinary function as Tracer.
+type TracerFunc func(event TraceEvent)
+
+// Trace calls f(event).
+func (f TracerFunc) Trace(event TraceEvent) {
+	f(event)
+}
+
+// NewSlogTracer returns a Tracer that writes every event as a structured
+// debug record into the specified handler.
+func NewSlogTracer(handler slog.Handler) Tracer {
+	return &slogTracer{logger: slog.New(handler)}
+}
+
+type slogTracer struct {
+	logger *slog.Logger
+}
+
+func (st *slogTracer) Trace(event TraceEvent) {
+	attrs := []slog.Attr{slog.String("reason", event.Reason)}
+	if event.Path != "" {
+		attrs = append(attrs, slog.String("path", event.Path))
+	}
+
+	switch event.Action {
+	case TraceConvert:
+		attrs = append(attrs, slog.String("tag", event.Tag))
+	case TraceScore:
+		attrs = append(attrs, slog.Float64("score", event.Score))
+	}
+
+	st.logger.LogAttrs(context.Background(), slog.LevelDebug, string(event.Action), attrs...)
+}
+
+// debugTracer is the tracer used when `Parser.Debug` is enabled without
+// any `Parser.Tracer`.
+var debugTracer = NewSlogTracer(slog.NewTextHandler(os.Stderr,
+	&slog.HandlerOptions{Level: slog.LevelDebug}))
+
+// trace reports the event to the tracer of the parser, if any.
+func (ps *Parser) trace(event TraceEvent) {
+	tracer := ps.Tracer
+	if tracer == nil && ps.Debug {
+		tracer = debugTracer
+	}
+
+	if tracer == nil {
+		return
+	}
+
+	if event.Node != nil && event.Path == "" {
+		event.Path = nodePath(event.Node)
+	}
+
+	tracer.Trace(event)
+}
+
+// traceInfo reports a TraceInfo event. The reason is only formatted
+// when the event will be reported somewhere.
+func (ps *Parser) traceInfo(format string, args ...interface{}) {
+	if ps.Tracer != nil || ps.Debug {
+		ps.trace(TraceEvent{Action: TraceInfo, Reason: fmt.Sprintf(format, args...)})
+	}
+}
+
+// nodePath returns a CSS-like path of the node from its root, e.g.
+// `html > body > div#main > p:nth-child(2)`.
+func nodePath(node *html.Node) string {
+	var parts []string
+	for ; node != nil && node.Type != html.DocumentNode; node = node.Parent {
+		parts = append(parts, nodePathPart(node))
+	}
+
+	for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+		parts[i], parts[j] = parts[j], parts[i]
+	}
+
+	return strings.Join(parts, " > ")
+}
+
+// nodePathPart returns the part of `nodePath` for a single node.
+func nodePathPart(node *html.Node) string {
+	switch node.Type {
+	case html.TextNode:
+		return "#text"
+	case html.CommentNode:
+		return "#comment"
+	case html.ElementNode:
+	default:
+		return "#node"
+	}
+
+	tagName := dom.TagName(node)
+	if id := dom.ID(node); id != "" {
+		return tagName + "#" + id
+	}
+
+	if tagName == "html" || tagName == "body" || tagName == "head" || node.Parent == nil {
+		return tagName
+	}
+
+	siblings := dom.Children(node.Parent)
+	if len(siblings) == 1 {
+		return tagName
+	}
+
+	index := indexOfNode(siblings, node)
+	return tagName + ":nth-child(" + strconv.Itoa(index+1) + ")"
+}
+
+// indexOfNode returns the position of the node in the list, or -1.
+func indexOfNode(nodes []*html.Node, node *html.Node) int {
+	for i, n := range nodes {
+		if n == node {
+			return i
+		}
+	}
+	return -1
+}
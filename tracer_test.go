@@ -0,0 +1,87 @@
+package readability
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func Test_Tracer(t *testing.T) {
+	content := loremParagraph(20)
+	source := `<html><body>` +
+		`<div id="sidebar"><p>Popular posts</p></div>` +
+		`<div style="display:none"><p>Hidden</p></div>` +
+		`<article><div>` + content + `</div><div>` + content + `</div></article>` +
+		`</body></html>`
+
+	var events []TraceEvent
+	parser := NewParser()
+	parser.Tracer = TracerFunc(func(event TraceEvent) {
+		events = append(events, event)
+	})
+
+	if _, err := parser.Parse(strings.NewReader(source), fakeHostURL); err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	expected := []TraceEvent{
+		{Action: TraceRemove, Path: "html > body > div#sidebar", Reason: "unlikely candidate"},
+		// The sidebar is already removed when the hidden node is checked
+		{Action: TraceRemove, Path: "html > body > div:nth-child(1)", Reason: "hidden node"},
+		{Action: TraceConvert, Path: "html > body > article > div:nth-child(1)", Tag: "p", Reason: "div with a single paragraph"},
+		{Action: TraceSelect, Path: "html > body > article", Reason: "top candidate"},
+	}
+
+	for _, want := range expected {
+		found := false
+		for _, event := range events {
+			if event.Action == want.Action && event.Path == want.Path &&
+				event.Tag == want.Tag && event.Reason == want.Reason {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			t.Errorf("missing event %+v", want)
+		}
+	}
+
+	var articleScore float64
+	for _, event := range events {
+		if event.Action == TraceScore && event.Path == "html > body > article" {
+			articleScore = event.Score
+		}
+	}
+
+	if articleScore <= 0 {
+		t.Errorf("article scored %v, want positive score", articleScore)
+	}
+}
+
+func Test_NewSlogTracer(t *testing.T) {
+	buffer := bytes.NewBuffer(nil)
+	tracer := NewSlogTracer(slog.NewJSONHandler(buffer, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	tracer.Trace(TraceEvent{Action: TraceScore, Path: "html > body > div", Score: 12.5, Reason: "paragraph score"})
+
+	var record map[string]interface{}
+	if err := json.Unmarshal(buffer.Bytes(), &record); err != nil {
+		t.Fatalf("failed to decode record %q: %v", buffer.String(), err)
+	}
+
+	expected := map[string]interface{}{
+		"msg":    "score",
+		"level":  "DEBUG",
+		"path":   "html > body > div",
+		"reason": "paragraph score",
+		"score":  12.5,
+	}
+
+	for key, value := range expected {
+		if record[key] != value {
+			t.Errorf("record %s, want %v got %v", key, value, record[key])
+		}
+	}
+}
@@ -3,6 +3,7 @@ package readability
 import (
 	nurl "net/url"
 	"strings"
+	"unicode"
 	"unicode/utf8"
 )
 
@@ -18,9 +19,64 @@ func indexOf(array []string, key string) int {
 	return -1
 }
 
-// wordCount returns number of word in str.
+// cjkPunctuation is the CJK Symbols and Punctuation block, along with the
+// Halfwidth and Fullwidth Forms block.
+var cjkPunctuation = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x3000, Hi: 0x303f, Stride: 1},
+		{Lo: 0xff00, Hi: 0xffef, Stride: 1},
+	},
+}
+
+// wordCount returns number of word in str. Most scripts separate words
+// with spaces, but Chinese and Japanese don't, so each Han, Hiragana and
+// Katakana char is counted as one word. Thai, Lao, Khmer and Myanmar don't
+// use spaces either, so their words are estimated from the letters count,
+// excluding the combining marks, since a word has about 3 letters.
 func wordCount(str string) int {
-	return len(strings.Fields(str))
+	count := 0
+	inWord := false
+	nSoutheastLetters := 0
+
+	flushSoutheast := func() {
+		count += (nSoutheastLetters + 2) / 3
+		nSoutheastLetters = 0
+	}
+
+	for _, r := range str {
+		switch {
+		case unicode.IsSpace(r):
+			inWord = false
+			flushSoutheast()
+
+		case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana):
+			inWord = false
+			flushSoutheast()
+			count++
+
+		case unicode.In(r, unicode.Thai, unicode.Lao, unicode.Khmer, unicode.Myanmar):
+			inWord = false
+			if unicode.IsLetter(r) {
+				nSoutheastLetters++
+			}
+
+		case unicode.IsPunct(r) && unicode.Is(cjkPunctuation, r):
+			// CJK punctuation, e.g. the ideographic full stop, separates
+			// words without any spaces.
+			inWord = false
+			flushSoutheast()
+
+		default:
+			flushSoutheast()
+			if !inWord {
+				inWord = true
+				count++
+			}
+		}
+	}
+
+	flushSoutheast()
+	return count
 }
 
 // charCount returns number of char in str.
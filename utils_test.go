@@ -34,6 +34,11 @@ func Test_wordCount(t *testing.T) {
 		"German fashion designer Karl Lagerfeld, best known for his creative work at Chanel, dies at the age of 85.":       19,
 		"A suicide bombing attack near Pulwama, in Indian administered Kashmir, kills 40 security personnel.":              14,
 		"NASA concludes the 15 year Opportunity Mars rover mission after being unable to wake the rover from hibernation.": 18,
+		"It’s a pen — isn't it?": 6,
+		"腾讯新闻，事实派。":              7,
+		"この記事はReadabilityのテストです": 12,
+		"ภาษาไทย":                3,
+		"中文 and English mixed":   5,
 	}
 
 	for sentence, expected := range scenarios {